@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/pkg/errors"
+)
+
+// Severity ...
+type Severity string
+
+const (
+	// SeverityError findings make the step unfit for sharing.
+	SeverityError Severity = "error"
+	// SeverityWarning findings are recommendations.
+	SeverityWarning Severity = "warning"
+)
+
+// TypeTags are the available type tags (categories) of a step.
+// https://github.com/bitrise-io/bitrise/blob/master/_docs/step-development-guideline.md#step-grouping-convention
+var TypeTags = []string{
+	"access-control", "artifact-info",
+	"installer", "deploy",
+	"utility", "dependency", "code-sign",
+	"build", "test", "notification",
+}
+
+// FindingModel ...
+type FindingModel struct {
+	Severity Severity `json:"severity"`
+	// Subject is what the finding is about: "step", "input (key)" or "output (key)"
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// String ...
+func (finding FindingModel) String() string {
+	return fmt.Sprintf("%s: %s", finding.Subject, finding.Message)
+}
+
+// StepYML parses the step.yml and audits it.
+func StepYML(pth string) ([]FindingModel, error) {
+	step, err := stepman.ParseStepDefinition(pth, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse step.yml (%s)", pth)
+	}
+	return Step(step), nil
+}
+
+// Step runs the share audit of stepman and the additional lint rules on the step,
+// and returns every finding at once.
+func Step(step models.StepModel) []FindingModel {
+	var findings []FindingModel
+	add := func(severity Severity, subject, format string, args ...interface{}) {
+		findings = append(findings, FindingModel{Severity: severity, Subject: subject, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// stepman's share audit stops at the first issue, so after reporting its error,
+	// its step level rules are checked one by one too, to report every finding at once
+	stepProperties := step
+	stepProperties.Inputs = nil
+	stepProperties.Outputs = nil
+	shareAuditMessage := ""
+	if err := stepProperties.AuditBeforeShare(); err != nil {
+		shareAuditMessage = err.Error()
+		add(SeverityError, "step", "%s", err)
+	}
+	for _, message := range shareAuditStepIssues(step) {
+		if message != shareAuditMessage {
+			add(SeverityError, "step", "%s", message)
+		}
+	}
+
+	if step.Source != nil && step.Source.Git != "" {
+		if !strings.HasPrefix(step.Source.Git, "http://") && !strings.HasPrefix(step.Source.Git, "https://") {
+			add(SeverityWarning, "step", "source.git should be an http:// or https:// URL")
+		}
+		if !strings.HasSuffix(step.Source.Git, ".git") {
+			add(SeverityWarning, "step", "source.git should end with .git")
+		}
+	}
+	if step.SourceCodeURL == nil || *step.SourceCodeURL == "" {
+		add(SeverityWarning, "step", "missing source_code_url")
+	}
+
+	for _, typeTag := range step.TypeTags {
+		if !slices.Contains(TypeTags, typeTag) {
+			add(SeverityWarning, "step", "unknown type tag (%s)", typeTag)
+		}
+	}
+
+	for _, env := range step.Inputs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			add(SeverityError, "input", "%s", err)
+			continue
+		}
+		subject := fmt.Sprintf("input (%s)", key)
+
+		if err := validateEnv(env); err != nil {
+			add(SeverityError, subject, "%s", err)
+		}
+
+		options, err := env.GetOptions()
+		if err != nil {
+			add(SeverityError, subject, "%s", err)
+			continue
+		}
+
+		if options.Summary == nil || *options.Summary == "" {
+			add(SeverityWarning, subject, "missing summary")
+		}
+
+		if options.IsSensitive != nil && *options.IsSensitive && value != "" {
+			add(SeverityError, subject, "sensitive input has a default value, it should be provided as a secret")
+		}
+
+		if len(options.ValueOptions) > 0 {
+			if value == "" {
+				add(SeverityError, subject, "input with value_options should have a default value")
+			} else if !slices.Contains(options.ValueOptions, value) {
+				add(SeverityError, subject, "default value (%s) is not one of the value_options (%v), so it can never be selected", value, options.ValueOptions)
+			}
+		}
+	}
+
+	for _, env := range step.Outputs {
+		key, _, err := env.GetKeyValuePair()
+		if err != nil {
+			add(SeverityError, "output", "%s", err)
+			continue
+		}
+
+		if err := validateEnv(env); err != nil {
+			add(SeverityError, fmt.Sprintf("output (%s)", key), "%s", err)
+		}
+	}
+
+	return findings
+}
+
+// shareAuditStepIssues returns every issue of the step level properties which StepModel.AuditBeforeShare checks,
+// with the same messages (AuditBeforeShare only returns the first one).
+func shareAuditStepIssues(step models.StepModel) []string {
+	var issues []string
+	for _, property := range []struct {
+		name  string
+		value *string
+	}{
+		{"title", step.Title},
+		{"summary", step.Summary},
+		{"website", step.Website},
+	} {
+		if property.value == nil || *property.value == "" {
+			issues = append(issues, fmt.Sprintf("Invalid step: missing or empty required '%s' property", property.name))
+		}
+	}
+	if step.Timeout != nil && *step.Timeout < 0 {
+		issues = append(issues, "Invalid step: timeout less then 0")
+	}
+	if step.NoOutputTimeout != nil && *step.NoOutputTimeout < 0 {
+		issues = append(issues, "Invalid step: 'no_output_timeout' is less then 0")
+	}
+	return issues
+}
+
+// Count returns the number of errors and warnings in the findings.
+func Count(findings []FindingModel) (errorCount int, warningCount int) {
+	for _, finding := range findings {
+		switch finding.Severity {
+		case SeverityError:
+			errorCount++
+		case SeverityWarning:
+			warningCount++
+		}
+	}
+	return
+}
+
+func validateEnv(env envmanModels.EnvironmentItemModel) error {
+	step := models.StepModel{Inputs: []envmanModels.EnvironmentItemModel{env}}
+	return step.ValidateInputAndOutputEnvs(true)
+}
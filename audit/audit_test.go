@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepYML(t *testing.T) {
+	t.Log("Valid step")
+	{
+		findings, err := StepYML("testdata/valid.yml")
+		require.NoError(t, err)
+		require.Empty(t, findings)
+	}
+
+	t.Log("Invalid step")
+	{
+		findings, err := StepYML("testdata/invalid.yml")
+		require.NoError(t, err)
+		require.Equal(t, []FindingModel{
+			{Severity: SeverityError, Subject: "step", Message: "Invalid step: missing or empty required 'summary' property"},
+			{Severity: SeverityError, Subject: "step", Message: "Invalid step: missing or empty required 'website' property"},
+			{Severity: SeverityWarning, Subject: "step", Message: "source.git should be an http:// or https:// URL"},
+			{Severity: SeverityWarning, Subject: "step", Message: "missing source_code_url"},
+			{Severity: SeverityWarning, Subject: "step", Message: "unknown type tag (magic)"},
+			{Severity: SeverityWarning, Subject: "input (no_summary)", Message: "missing summary"},
+			{Severity: SeverityError, Subject: "input (api_token)", Message: "sensitive input has a default value, it should be provided as a secret"},
+			{Severity: SeverityError, Subject: "input (mode)", Message: "default value (medium) is not one of the value_options ([fast slow]), so it can never be selected"},
+			{Severity: SeverityError, Subject: "input (no_default)", Message: "input with value_options should have a default value"},
+			{Severity: SeverityError, Subject: "output (NO_TITLE)", Message: "Invalid environment (NO_TITLE), err: missing or empty title"},
+		}, findings)
+
+		errorCount, warningCount := Count(findings)
+		require.Equal(t, 6, errorCount)
+		require.Equal(t, 4, warningCount)
+	}
+}
+
+func TestShareAuditStepIssues(t *testing.T) {
+	validStep := func() models.StepModel {
+		return models.StepModel{
+			Title:   pointers.NewStringPtr("Title"),
+			Summary: pointers.NewStringPtr("Summary"),
+			Website: pointers.NewStringPtr("https://github.com/me/bitrise-step-valid"),
+		}
+	}
+
+	step := validStep()
+	require.NoError(t, step.AuditBeforeShare())
+	require.Empty(t, shareAuditStepIssues(step))
+
+	// every rule has to report the same issue as stepman's share audit
+	for _, invalidate := range []func(step *models.StepModel){
+		func(step *models.StepModel) { step.Title = nil },
+		func(step *models.StepModel) { step.Summary = pointers.NewStringPtr("") },
+		func(step *models.StepModel) { step.Website = nil },
+		func(step *models.StepModel) { step.Timeout = pointers.NewIntPtr(-1) },
+		func(step *models.StepModel) { step.NoOutputTimeout = pointers.NewIntPtr(-1) },
+	} {
+		step := validStep()
+		invalidate(&step)
+		err := step.AuditBeforeShare()
+		require.Error(t, err)
+		require.Equal(t, []string{err.Error()}, shareAuditStepIssues(step))
+	}
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steprun"
+)
+
+// Outputs compares the envs exported by a run of the step with the outputs declared in its step.yml.
+// Declared outputs which were not exported are errors,
+// exported envs which are not declared and outputs exported with an empty value are warnings.
+func Outputs(step models.StepModel, exported []steprun.OutputModel) ([]FindingModel, error) {
+	var findings []FindingModel
+	add := func(severity Severity, key, message string) {
+		findings = append(findings, FindingModel{Severity: severity, Subject: fmt.Sprintf("output (%s)", key), Message: message})
+	}
+
+	exportedByKey := map[string]steprun.OutputModel{}
+	for _, output := range exported {
+		exportedByKey[output.Key] = output
+	}
+
+	for _, env := range step.Outputs {
+		key, _, err := env.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read output")
+		}
+
+		output, found := exportedByKey[key]
+		switch {
+		case !found:
+			add(SeverityError, key, "declared in the step.yml, but not exported")
+		case output.Value == "":
+			add(SeverityWarning, key, "exported with an empty value")
+		}
+	}
+
+	for _, output := range exported {
+		if !output.IsDeclared {
+			add(SeverityWarning, output.Key, "exported, but not declared in the step.yml")
+		}
+	}
+	return findings, nil
+}
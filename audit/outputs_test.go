@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steprun"
+)
+
+func TestOutputs(t *testing.T) {
+	step := models.StepModel{Outputs: []envmanModels.EnvironmentItemModel{
+		{"EXPORTED": ""},
+		{"MISSING": ""},
+		{"EMPTY": ""},
+	}}
+
+	findings, err := Outputs(step, []steprun.OutputModel{
+		{Key: "EXPORTED", Value: "value", IsDeclared: true},
+		{Key: "EMPTY", Value: "", IsDeclared: true},
+		{Key: "EXTRA", Value: "value"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []FindingModel{
+		{Severity: SeverityError, Subject: "output (MISSING)", Message: "declared in the step.yml, but not exported"},
+		{Severity: SeverityWarning, Subject: "output (EMPTY)", Message: "exported with an empty value"},
+		{Severity: SeverityWarning, Subject: "output (EXTRA)", Message: "exported, but not declared in the step.yml"},
+	}, findings)
+
+	findings, err = Outputs(step, []steprun.OutputModel{
+		{Key: "EXPORTED", Value: "value", IsDeclared: true},
+		{Key: "MISSING", Value: "value", IsDeclared: true},
+		{Key: "EMPTY", Value: "value", IsDeclared: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+}
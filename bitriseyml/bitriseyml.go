@@ -0,0 +1,103 @@
+package bitriseyml
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// StepReferenceModel is a reference of a StepLib step in a workflow.
+type StepReferenceModel struct {
+	Workflow string `json:"workflow"`
+	// Index is the position of the step in the workflow
+	Index int `json:"index"`
+	// Composite is the step reference as it is in the bitrise.yml, e.g. git-clone@8
+	Composite string `json:"composite"`
+	StepLib   string `json:"steplib"`
+	ID        string `json:"id"`
+	// Version is the version (constraint) of the reference, empty if the step is not pinned to a version
+	Version string `json:"version,omitempty"`
+	// Inputs are the inputs set for the step in the workflow
+	Inputs []envmanModels.EnvironmentItemModel `json:"-"`
+}
+
+// ReadConfig reads and parses the bitrise.yml.
+func ReadConfig(pth string) (models.BitriseDataModel, error) {
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return models.BitriseDataModel{}, errors.Wrapf(err, "Failed to read bitrise.yml (%s)", pth)
+	}
+
+	var config models.BitriseDataModel
+	if err := yaml.Unmarshal(bytes, &config); err != nil {
+		return models.BitriseDataModel{}, errors.Wrapf(err, "Failed to parse bitrise.yml (%s)", pth)
+	}
+	return config, nil
+}
+
+// StepReferences returns the StepLib step references of every workflow of the config,
+// ordered by workflow ID and position in the workflow. References without StepLib source
+// are resolved against the default_step_lib_source, or the official Bitrise StepLib.
+// Local (path::), direct git (git::) and StepLib independent (_::) steps,
+// step bundles and with groups are not StepLib references, these are skipped.
+func StepReferences(config models.BitriseDataModel) ([]StepReferenceModel, error) {
+	defaultStepLibSource := config.DefaultStepLibSource
+	if defaultStepLibSource == "" {
+		defaultStepLibSource = stepmanutil.DefaultCollectionURI
+	}
+
+	workflowIDs := make([]string, 0, len(config.Workflows))
+	for workflowID := range config.Workflows {
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	sort.Strings(workflowIDs)
+
+	var references []StepReferenceModel
+	for _, workflowID := range workflowIDs {
+		for idx, stepListItem := range config.Workflows[workflowID].Steps {
+			composite, stepData, err := models.GetStepIDStepDataPair(stepListItem)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid step (#%d) in workflow (%s)", idx+1, workflowID)
+			}
+			if !isStepLibReference(composite) {
+				continue
+			}
+
+			idData, err := models.CreateStepIDDataFromString(composite, defaultStepLibSource)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid step (%s) in workflow (%s)", composite, workflowID)
+			}
+
+			references = append(references, StepReferenceModel{
+				Workflow:  workflowID,
+				Index:     idx,
+				Composite: composite,
+				StepLib:   idData.SteplibSource,
+				ID:        idData.IDorURI,
+				Version:   idData.Version,
+				Inputs:    stepData.Inputs,
+			})
+		}
+	}
+	return references, nil
+}
+
+func isStepLibReference(composite string) bool {
+	if composite == "with" {
+		return false
+	}
+	if source, _, found := strings.Cut(composite, "::"); found {
+		switch source {
+		case "path", "git", "_", "bundle":
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package bitriseyml
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepReferences(t *testing.T) {
+	t.Log("StepLib references, ordered by workflow")
+	{
+		config, err := ReadConfig("./testdata/bitrise.yml")
+		require.NoError(t, err)
+
+		references, err := StepReferences(config)
+		require.NoError(t, err)
+
+		steplib := "https://github.com/bitrise-io/bitrise-steplib.git"
+		require.Equal(t, []StepReferenceModel{
+			{Workflow: "deploy", Index: 0, Composite: "https://github.com/my-org/my-steplib.git::my-deploy@0.1.0", StepLib: "https://github.com/my-org/my-steplib.git", ID: "my-deploy", Version: "0.1.0"},
+			{Workflow: "deploy", Index: 2, Composite: "deploy-to-bitrise-io@2.1.3", StepLib: steplib, ID: "deploy-to-bitrise-io", Version: "2.1.3"},
+			{Workflow: "primary", Index: 0, Composite: "activate-ssh-key@4", StepLib: steplib, ID: "activate-ssh-key", Version: "4"},
+			{Workflow: "primary", Index: 1, Composite: "git-clone@8.1", StepLib: steplib, ID: "git-clone", Version: "8.1", Inputs: []envmanModels.EnvironmentItemModel{{"clone_depth": 1}}},
+			{Workflow: "primary", Index: 4, Composite: "script", StepLib: steplib, ID: "script", Version: "", Inputs: []envmanModels.EnvironmentItemModel{{"content": `echo "hello"`}}},
+		}, references)
+	}
+
+	t.Log("default_step_lib_source is used for references without StepLib source")
+	{
+		config := models.BitriseDataModel{
+			DefaultStepLibSource: "https://github.com/my-org/my-steplib.git",
+			Workflows: map[string]models.WorkflowModel{
+				"primary": {Steps: []models.StepListItemModel{{"script@1": {}}}},
+			},
+		}
+
+		references, err := StepReferences(config)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(references))
+		require.Equal(t, "https://github.com/my-org/my-steplib.git", references[0].StepLib)
+	}
+
+	t.Log("missing bitrise.yml")
+	{
+		_, err := ReadConfig("./testdata/not-existing.yml")
+		require.Error(t, err)
+	}
+}
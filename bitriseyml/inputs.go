@@ -0,0 +1,167 @@
+package bitriseyml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// InputIssueModel is an issue of a step reference's inputs, compared to the step's definition.
+type InputIssueModel struct {
+	StepReferenceModel
+	ResolvedVersion string         `json:"resolved_version,omitempty"`
+	Severity        audit.Severity `json:"severity"`
+	// Input is the key of the input, empty if the issue is about the step itself
+	Input   string `json:"input,omitempty"`
+	Message string `json:"message"`
+}
+
+// String ...
+func (issue InputIssueModel) String() string {
+	if issue.Input == "" {
+		return issue.Message
+	}
+	return fmt.Sprintf("input (%s): %s", issue.Input, issue.Message)
+}
+
+// CheckReferenceInputs resolves the step reference against the StepLib it refers to,
+// and checks the inputs of the reference against the resolved step version, see CheckInputs.
+// A step or version which does not exist in the StepLib is an error issue.
+func CheckReferenceInputs(reference StepReferenceModel, collection models.StepCollectionModel) ([]InputIssueModel, error) {
+	status := CheckReference(reference, collection)
+	if status.Error != "" {
+		return []InputIssueModel{{StepReferenceModel: reference, Severity: audit.SeverityError, Message: status.Error}}, nil
+	}
+
+	step, _, err := stepmanutil.StepVersion(collection, reference.ID, status.ResolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := CheckInputs(reference, step)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to check the inputs of %s in workflow (%s)", reference.Composite, reference.Workflow)
+	}
+	for i := range issues {
+		issues[i].ResolvedVersion = status.ResolvedVersion
+	}
+	return issues, nil
+}
+
+// stepInputModel is an input of the step definition.
+type stepInputModel struct {
+	defaultValue string
+	options      envmanModels.EnvironmentItemOptionsModel
+}
+
+// CheckInputs checks the inputs set in the workflow against the inputs of the step:
+// - inputs which are not defined by the step (error)
+// - values which are not one of the input's value_options (error), unless the value references an env var
+// - required inputs without value: not set in the workflow and without default value (error)
+// - is_dont_change_value inputs set to a value other than their default (warning)
+func CheckInputs(reference StepReferenceModel, step models.StepModel) ([]InputIssueModel, error) {
+	var issues []InputIssueModel
+	add := func(severity audit.Severity, key, format string, args ...interface{}) {
+		issues = append(issues, InputIssueModel{StepReferenceModel: reference, Severity: severity, Input: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var stepInputKeys []string
+	stepInputs := map[string]stepInputModel{}
+	for _, env := range step.Inputs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid step input")
+		}
+		options, err := env.GetOptions()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid options of step input (%s)", key)
+		}
+		stepInputKeys = append(stepInputKeys, key)
+		stepInputs[key] = stepInputModel{defaultValue: value, options: options}
+	}
+
+	values := map[string]string{}
+	for _, env := range reference.Inputs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid input")
+		}
+		values[key] = value
+
+		stepInput, found := stepInputs[key]
+		if !found {
+			if suggestion, found := closestKey(key, stepInputKeys); found {
+				add(audit.SeverityError, key, "not an input of the step, did you mean %s?", suggestion)
+			} else {
+				add(audit.SeverityError, key, "not an input of the step")
+			}
+			continue
+		}
+
+		if len(stepInput.options.ValueOptions) > 0 && !strings.Contains(value, "$") && !slices.Contains(stepInput.options.ValueOptions, value) {
+			add(audit.SeverityError, key, "value (%s) is not one of the value options: %s", value, strings.Join(stepInput.options.ValueOptions, ", "))
+		}
+		if pointers.BoolWithDefault(stepInput.options.IsDontChangeValue, envmanModels.DefaultIsDontChangeValue) && value != stepInput.defaultValue {
+			add(audit.SeverityWarning, key, "should not be changed (is_dont_change_value), default value: %s", stepInput.defaultValue)
+		}
+	}
+
+	for _, key := range stepInputKeys {
+		stepInput := stepInputs[key]
+		if !pointers.BoolWithDefault(stepInput.options.IsRequired, envmanModels.DefaultIsRequired) {
+			continue
+		}
+		value, found := values[key]
+		if !found {
+			value = stepInput.defaultValue
+		}
+		if value == "" {
+			add(audit.SeverityError, key, "required, but has no value")
+		}
+	}
+	return issues, nil
+}
+
+// closestKey returns the key which is the most similar to the given one, if any of them is similar enough
+// to be a typo: at most 2 edits away, and less than half of the key has to be changed.
+func closestKey(key string, keys []string) (string, bool) {
+	closest, closestDistance := "", -1
+	for _, candidate := range keys {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(candidate))
+		if distance > 2 || 2*distance >= len(candidate) {
+			continue
+		}
+		if closestDistance == -1 || distance < closestDistance {
+			closest, closestDistance = candidate, distance
+		}
+	}
+	return closest, closestDistance != -1
+}
+
+// editDistance returns the Levenshtein distance of the strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,120 @@
+package bitriseyml
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
+)
+
+func TestCheckInputs(t *testing.T) {
+	step := models.StepModel{Inputs: []envmanModels.EnvironmentItemModel{
+		{"repository_url": "$GIT_REPOSITORY_URL", envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{IsRequired: pointers.NewBoolPtr(true)}},
+		{"api_token": "", envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{IsRequired: pointers.NewBoolPtr(true)}},
+		{"clone_depth": ""},
+		{"merge_pr": "yes", envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{ValueOptions: []string{"yes", "no"}}},
+		{"build_url": "$BITRISE_BUILD_URL", envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{IsDontChangeValue: pointers.NewBoolPtr(true)}},
+	}}
+
+	t.Log("valid inputs")
+	{
+		reference := StepReferenceModel{ID: "git-clone", Inputs: []envmanModels.EnvironmentItemModel{
+			{"api_token": "$TOKEN"},
+			{"clone_depth": 1},
+			{"merge_pr": "no"},
+			{"build_url": "$BITRISE_BUILD_URL"},
+		}}
+		issues, err := CheckInputs(reference, step)
+		require.NoError(t, err)
+		require.Empty(t, issues)
+
+		t.Log("value options are not checked for env var references")
+		reference.Inputs = append(reference.Inputs, envmanModels.EnvironmentItemModel{"merge_pr": "$MERGE_PR"})
+		issues, err = CheckInputs(reference, step)
+		require.NoError(t, err)
+		require.Empty(t, issues)
+	}
+
+	t.Log("invalid inputs")
+	{
+		reference := StepReferenceModel{ID: "git-clone", Inputs: []envmanModels.EnvironmentItemModel{
+			{"clone_dpeth": 1},
+			{"unknown_input": "value"},
+			{"merge_pr": "false"},
+			{"build_url": "https://example.com"},
+			{"repository_url": ""},
+		}}
+		issues, err := CheckInputs(reference, step)
+		require.NoError(t, err)
+
+		var got []string
+		for _, issue := range issues {
+			got = append(got, string(issue.Severity)+": "+issue.String())
+		}
+		require.Equal(t, []string{
+			"error: input (clone_dpeth): not an input of the step, did you mean clone_depth?",
+			"error: input (unknown_input): not an input of the step",
+			"error: input (merge_pr): value (false) is not one of the value options: yes, no",
+			"warning: input (build_url): should not be changed (is_dont_change_value), default value: $BITRISE_BUILD_URL",
+			"error: input (repository_url): required, but has no value",
+			"error: input (api_token): required, but has no value",
+		}, got)
+	}
+}
+
+func TestCheckReferenceInputs(t *testing.T) {
+	collection := models.StepCollectionModel{
+		Steps: models.StepHash{
+			"script": models.StepGroupModel{
+				LatestVersionNumber: "1.1.0",
+				Versions: map[string]models.StepModel{
+					"1.0.0": {Inputs: []envmanModels.EnvironmentItemModel{{"content": ""}}},
+					"1.1.0": {Inputs: []envmanModels.EnvironmentItemModel{{"content": ""}, {"runner_bin": "/bin/bash"}}},
+				},
+			},
+		},
+	}
+	reference := StepReferenceModel{ID: "script", Inputs: []envmanModels.EnvironmentItemModel{{"runner_bin": "/bin/zsh"}}}
+
+	issues, err := CheckReferenceInputs(reference, collection)
+	require.NoError(t, err)
+	require.Empty(t, issues)
+
+	reference.Version = "1.0"
+	issues, err = CheckReferenceInputs(reference, collection)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(issues))
+	require.Equal(t, "1.0.0", issues[0].ResolvedVersion)
+	require.Equal(t, "input (runner_bin): not an input of the step", issues[0].String())
+
+	reference.Version = "2"
+	issues, err = CheckReferenceInputs(reference, collection)
+	require.NoError(t, err)
+	require.Equal(t, []InputIssueModel{{StepReferenceModel: reference, Severity: audit.SeverityError, Message: "version (2) does not exist"}}, issues)
+}
+
+func TestClosestKey(t *testing.T) {
+	keys := []string{"clone_depth", "repository_url", "tag", "branch"}
+
+	key, found := closestKey("clone_dept", keys)
+	require.True(t, found)
+	require.Equal(t, "clone_depth", key)
+
+	key, found = closestKey("Branch", keys)
+	require.True(t, found)
+	require.Equal(t, "branch", key)
+
+	key, found = closestKey("tig", keys)
+	require.True(t, found)
+	require.Equal(t, "tag", key)
+
+	_, found = closestKey("ab", keys)
+	require.False(t, found)
+
+	_, found = closestKey("commit", keys)
+	require.False(t, found)
+}
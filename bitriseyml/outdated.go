@@ -0,0 +1,57 @@
+package bitriseyml
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/stepman/models"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// ReferenceStatusModel is the state of a step reference, compared to the StepLib.
+type ReferenceStatusModel struct {
+	StepReferenceModel
+	// ResolvedVersion is the version the reference resolves to, respecting major and minor locks
+	ResolvedVersion string `json:"resolved_version,omitempty"`
+	LatestVersion   string `json:"latest_version,omitempty"`
+	// IsOutdated is true if the latest version of the step is higher than the resolved version,
+	// e.g. git-clone@7 is outdated if git-clone 8.0.0 is available
+	IsOutdated     bool   `json:"is_outdated"`
+	IsDeprecated   bool   `json:"is_deprecated"`
+	DeprecateNotes string `json:"deprecate_notes,omitempty"`
+	RemovalDate    string `json:"removal_date,omitempty"`
+	// Error is set if the step or the referenced version does not exist in the StepLib
+	Error string `json:"error,omitempty"`
+}
+
+// IsUpToDate ...
+func (status ReferenceStatusModel) IsUpToDate() bool {
+	return status.Error == "" && !status.IsOutdated && !status.IsDeprecated
+}
+
+// CheckReference resolves the step reference against the StepLib it refers to.
+func CheckReference(reference StepReferenceModel, collection models.StepCollectionModel) ReferenceStatusModel {
+	status := ReferenceStatusModel{StepReferenceModel: reference}
+
+	stepGroup, found := collection.Steps[reference.ID]
+	if !found {
+		status.Error = "step not found in the StepLib"
+		return status
+	}
+	status.LatestVersion = stepGroup.LatestVersionNumber
+	status.DeprecateNotes = stepGroup.Info.DeprecateNotes
+	status.RemovalDate = stepGroup.Info.RemovalDate
+	status.IsDeprecated = status.DeprecateNotes != "" || status.RemovalDate != ""
+
+	resolution, err := stepmanutil.ResolveStepVersion(collection, reference.ID, reference.Version)
+	if err != nil {
+		status.Error = fmt.Sprintf("version (%s) does not exist", reference.Version)
+		return status
+	}
+	status.ResolvedVersion = resolution.Version
+
+	if compare, err := stepmanutil.CompareVersions(status.LatestVersion, status.ResolvedVersion); err == nil && compare > 0 {
+		status.IsOutdated = true
+	}
+	return status
+}
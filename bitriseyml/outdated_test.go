@@ -0,0 +1,82 @@
+package bitriseyml
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReference(t *testing.T) {
+	collection := models.StepCollectionModel{
+		Steps: models.StepHash{
+			"git-clone": models.StepGroupModel{
+				LatestVersionNumber: "8.2.0",
+				Versions: map[string]models.StepModel{
+					"7.0.0": {}, "8.0.0": {}, "8.1.0": {}, "8.1.2": {}, "8.2.0": {},
+				},
+			},
+			"old-deploy": models.StepGroupModel{
+				Info:                models.StepGroupInfoModel{DeprecateNotes: "Use deploy-to-bitrise-io", RemovalDate: "2025-01-01"},
+				LatestVersionNumber: "1.0.0",
+				Versions:            map[string]models.StepModel{"1.0.0": {}},
+			},
+		},
+	}
+	check := func(id, version string) ReferenceStatusModel {
+		return CheckReference(StepReferenceModel{ID: id, Version: version}, collection)
+	}
+
+	t.Log("latest major lock is up to date")
+	{
+		status := check("git-clone", "8")
+		require.Equal(t, "8.2.0", status.ResolvedVersion)
+		require.True(t, status.IsUpToDate())
+	}
+
+	t.Log("minor lock resolves to the latest patch, but a newer minor is available")
+	{
+		status := check("git-clone", "8.1")
+		require.Equal(t, "8.1.2", status.ResolvedVersion)
+		require.Equal(t, "8.2.0", status.LatestVersion)
+		require.True(t, status.IsOutdated)
+		require.Equal(t, "", status.Error)
+	}
+
+	t.Log("old major lock is outdated")
+	{
+		status := check("git-clone", "7")
+		require.Equal(t, "7.0.0", status.ResolvedVersion)
+		require.True(t, status.IsOutdated)
+	}
+
+	t.Log("unpinned reference is always the latest")
+	{
+		status := check("git-clone", "")
+		require.Equal(t, "8.2.0", status.ResolvedVersion)
+		require.True(t, status.IsUpToDate())
+	}
+
+	t.Log("version does not exist")
+	{
+		status := check("git-clone", "9")
+		require.Equal(t, "version (9) does not exist", status.Error)
+		require.Equal(t, "", status.ResolvedVersion)
+		require.False(t, status.IsUpToDate())
+	}
+
+	t.Log("step does not exist")
+	{
+		status := check("not-existing", "1")
+		require.Equal(t, "step not found in the StepLib", status.Error)
+	}
+
+	t.Log("deprecated step")
+	{
+		status := check("old-deploy", "1")
+		require.True(t, status.IsDeprecated)
+		require.False(t, status.IsOutdated)
+		require.Equal(t, "2025-01-01", status.RemovalDate)
+		require.False(t, status.IsUpToDate())
+	}
+}
@@ -0,0 +1,196 @@
+package bitriseyml
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// Upgrade policies
+const (
+	// PolicyPatch upgrades to the latest patch version of the same minor version
+	PolicyPatch = "patch"
+	// PolicyMinor upgrades to the latest minor version of the same major version
+	PolicyMinor = "minor"
+	// PolicyMajor upgrades to the latest version
+	PolicyMajor = "major"
+	// PolicyLock upgrades to the latest version, keeping the precision of the reference:
+	// git-clone@7 becomes git-clone@8, git-clone@7.1 becomes git-clone@8.2
+	PolicyLock = "lock"
+)
+
+// Policies are the available upgrade policies.
+var Policies = []string{PolicyPatch, PolicyMinor, PolicyMajor, PolicyLock}
+
+// UpgradeModel is a step reference upgrade.
+type UpgradeModel struct {
+	StepReferenceModel
+	// FromVersion is the version the reference currently resolves to
+	FromVersion string `json:"from_version"`
+	// ToVersion is the version the new reference resolves to
+	ToVersion string `json:"to_version"`
+	// NewComposite is the new step reference, e.g. git-clone@8.2.0
+	NewComposite string `json:"new_composite"`
+}
+
+// PlanUpgrade returns the upgrade of the step reference within the policy,
+// found is false if the reference is up to date within the policy or it's not pinned to a version.
+// The patch, minor and major policies pin the reference to the full version (e.g. git-clone@8.2.0).
+func PlanUpgrade(reference StepReferenceModel, collection models.StepCollectionModel, policy string) (UpgradeModel, bool, error) {
+	if reference.Version == "" {
+		return UpgradeModel{}, false, nil
+	}
+
+	resolution, err := stepmanutil.ResolveStepVersion(collection, reference.ID, reference.Version)
+	if err != nil {
+		return UpgradeModel{}, false, err
+	}
+	from := resolution.Version
+
+	parts := strings.Split(from, ".")
+	if len(parts) != 3 {
+		return UpgradeModel{}, false, errors.Errorf("Invalid version (%s) of step (%s), it's not in X.Y.Z format", from, reference.ID)
+	}
+
+	var constraint string
+	switch policy {
+	case PolicyPatch:
+		constraint = parts[0] + "." + parts[1] + ".x"
+	case PolicyMinor:
+		constraint = parts[0] + ".x.x"
+	case PolicyMajor, PolicyLock:
+	default:
+		return UpgradeModel{}, false, errors.Errorf("Invalid upgrade policy (%s), available: %s", policy, strings.Join(Policies, ", "))
+	}
+
+	target, err := stepmanutil.ResolveStepVersion(collection, reference.ID, constraint)
+	if err != nil {
+		return UpgradeModel{}, false, err
+	}
+	to := target.Version
+
+	if compare, err := stepmanutil.CompareVersions(to, from); err != nil || compare <= 0 {
+		return UpgradeModel{}, false, nil
+	}
+
+	newVersion := to
+	if policy == PolicyLock {
+		newVersion = lockStyleVersion(reference.Version, to)
+		if newVersion == reference.Version {
+			return UpgradeModel{}, false, nil
+		}
+	}
+
+	idx := strings.LastIndex(reference.Composite, "@")
+	if idx == -1 {
+		return UpgradeModel{}, false, errors.Errorf("Invalid step reference (%s), no version found", reference.Composite)
+	}
+
+	return UpgradeModel{
+		StepReferenceModel: reference,
+		FromVersion:        from,
+		ToVersion:          to,
+		NewComposite:       reference.Composite[:idx+1] + newVersion,
+	}, true, nil
+}
+
+// lockStyleVersion returns the version in the same format as the constraint,
+// e.g. 8.2.0 in the format of 7 is 8, in the format of 7.1.x is 8.2.x.
+func lockStyleVersion(constraint, version string) string {
+	constraintParts := strings.Split(constraint, ".")
+	versionParts := strings.Split(version, ".")
+
+	parts := make([]string, len(constraintParts))
+	for i, part := range constraintParts {
+		if part == "x" || i >= len(versionParts) {
+			parts[i] = part
+		} else {
+			parts[i] = versionParts[i]
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// RewriteStepReferences replaces the step references (step list item keys) of the workflows' steps lists
+// in the bitrise.yml content, replacements maps the old step reference to the new one.
+// Every other line of the content is kept as it is, including comments and formatting.
+// Returns the new content and the number of replaced references.
+func RewriteStepReferences(content string, replacements map[string]string) (string, int, error) {
+	keyLines, err := stepListItemKeyLines(content)
+	if err != nil {
+		return "", 0, err
+	}
+
+	lines := strings.Split(content, "\n")
+	count := 0
+	for i, line := range lines {
+		// yaml node lines start from 1
+		if !keyLines[i+1] {
+			continue
+		}
+		match := stepListItemKeyRegexp.FindStringSubmatch(line)
+		if match == nil || match[2] != match[4] {
+			continue
+		}
+		newComposite, found := replacements[match[3]]
+		if !found {
+			continue
+		}
+		lines[i] = fmt.Sprintf("%s%s%s%s%s", match[1], match[2], newComposite, match[4], match[5])
+		count++
+	}
+	return strings.Join(lines, "\n"), count, nil
+}
+
+// stepListItemKeyLines returns the lines of the step list item keys (the step references)
+// of the workflows' steps lists, other list items with a similar format are not step references.
+func stepListItemKeyLines(content string) (map[int]bool, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse bitrise.yml")
+	}
+
+	keyLines := map[int]bool{}
+	if len(document.Content) == 0 {
+		return keyLines, nil
+	}
+	workflows := mappingValue(document.Content[0], "workflows")
+	if workflows == nil || workflows.Kind != yaml.MappingNode {
+		return keyLines, nil
+	}
+	for i := 1; i < len(workflows.Content); i += 2 {
+		steps := mappingValue(workflows.Content[i], "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, stepListItem := range steps.Content {
+			if stepListItem.Kind == yaml.MappingNode && len(stepListItem.Content) > 0 {
+				keyLines[stepListItem.Content[0].Line] = true
+			}
+		}
+	}
+	return keyLines, nil
+}
+
+// mappingValue returns the value of the key in the mapping node, or nil if it's not found.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// stepListItemKeyRegexp matches a step list item key: indentation and dash, opening quote,
+// step reference, closing quote, colon and the rest of the line
+var stepListItemKeyRegexp = regexp.MustCompile(`^(\s*-\s+)(["']?)([^"'\s:#][^"'\s]*?)(["']?)(\s*:(?:\s.*)?\r?)$`)
@@ -0,0 +1,148 @@
+package bitriseyml
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanUpgrade(t *testing.T) {
+	collection := models.StepCollectionModel{
+		Steps: models.StepHash{
+			"git-clone": models.StepGroupModel{
+				LatestVersionNumber: "8.2.0",
+				Versions: map[string]models.StepModel{
+					"7.0.0": {}, "7.0.1": {}, "8.0.0": {}, "8.1.0": {}, "8.1.2": {}, "8.2.0": {},
+				},
+			},
+		},
+	}
+	plan := func(composite, version, policy string) (UpgradeModel, bool) {
+		reference := StepReferenceModel{Composite: composite, ID: "git-clone", Version: version}
+		upgrade, found, err := PlanUpgrade(reference, collection, policy)
+		require.NoError(t, err)
+		return upgrade, found
+	}
+
+	t.Log("patch policy")
+	{
+		upgrade, found := plan("git-clone@8.1.0", "8.1.0", PolicyPatch)
+		require.True(t, found)
+		require.Equal(t, "git-clone@8.1.2", upgrade.NewComposite)
+		require.Equal(t, "8.1.0", upgrade.FromVersion)
+		require.Equal(t, "8.1.2", upgrade.ToVersion)
+
+		_, found = plan("git-clone@8.2.0", "8.2.0", PolicyPatch)
+		require.False(t, found)
+	}
+
+	t.Log("minor policy")
+	{
+		upgrade, found := plan("git-clone@8.1.0", "8.1.0", PolicyMinor)
+		require.True(t, found)
+		require.Equal(t, "git-clone@8.2.0", upgrade.NewComposite)
+
+		upgrade, found = plan("git-clone@7.0.0", "7.0.0", PolicyMinor)
+		require.True(t, found)
+		require.Equal(t, "git-clone@7.0.1", upgrade.NewComposite)
+
+		_, found = plan("git-clone@8", "8", PolicyMinor)
+		require.False(t, found)
+	}
+
+	t.Log("major policy")
+	{
+		upgrade, found := plan("git-clone@7", "7", PolicyMajor)
+		require.True(t, found)
+		require.Equal(t, "git-clone@8.2.0", upgrade.NewComposite)
+		require.Equal(t, "7.0.1", upgrade.FromVersion)
+	}
+
+	t.Log("lock policy keeps the precision of the reference")
+	{
+		upgrade, found := plan("git-clone@7", "7", PolicyLock)
+		require.True(t, found)
+		require.Equal(t, "git-clone@8", upgrade.NewComposite)
+
+		upgrade, found = plan("git-clone@8.1.x", "8.1.x", PolicyLock)
+		require.True(t, found)
+		require.Equal(t, "git-clone@8.2.x", upgrade.NewComposite)
+
+		_, found = plan("git-clone@8", "8", PolicyLock)
+		require.False(t, found)
+	}
+
+	t.Log("StepLib source is kept")
+	{
+		upgrade, found := plan("https://github.com/bitrise-io/bitrise-steplib.git::git-clone@7.0.0", "7.0.0", PolicyMajor)
+		require.True(t, found)
+		require.Equal(t, "https://github.com/bitrise-io/bitrise-steplib.git::git-clone@8.2.0", upgrade.NewComposite)
+	}
+
+	t.Log("not pinned reference")
+	{
+		_, found := plan("git-clone", "", PolicyMajor)
+		require.False(t, found)
+	}
+
+	t.Log("not existing version")
+	{
+		reference := StepReferenceModel{Composite: "git-clone@9", ID: "git-clone", Version: "9"}
+		_, _, err := PlanUpgrade(reference, collection, PolicyMajor)
+		require.Error(t, err)
+	}
+
+	t.Log("invalid policy")
+	{
+		reference := StepReferenceModel{Composite: "git-clone@7", ID: "git-clone", Version: "7"}
+		_, _, err := PlanUpgrade(reference, collection, "invalid")
+		require.Error(t, err)
+	}
+}
+
+func TestRewriteStepReferences(t *testing.T) {
+	content := `workflows:
+  primary:
+    steps:
+    # clone the repository
+    - git-clone@8.1: # pinned to minor
+        inputs:
+        - clone_depth: 1
+    - "script@1.0.0": {}
+    - https://github.com/my-org/my-steplib.git::my-deploy@0.1.0: {}
+    - script@1.0.0:
+        title: script@1.0.0
+  deploy:
+    envs:
+    - script@1.0.0: not a step
+    steps:
+    - git-clone@8.1: {}
+`
+	expected := `workflows:
+  primary:
+    steps:
+    # clone the repository
+    - git-clone@8.2.0: # pinned to minor
+        inputs:
+        - clone_depth: 1
+    - "script@1.1.0": {}
+    - https://github.com/my-org/my-steplib.git::my-deploy@0.2.0: {}
+    - script@1.1.0:
+        title: script@1.0.0
+  deploy:
+    envs:
+    - script@1.0.0: not a step
+    steps:
+    - git-clone@8.2.0: {}
+`
+
+	rewritten, count, err := RewriteStepReferences(content, map[string]string{
+		"git-clone@8.1": "git-clone@8.2.0",
+		"script@1.0.0":  "script@1.1.0",
+		"https://github.com/my-org/my-steplib.git::my-deploy@0.1.0": "https://github.com/my-org/my-steplib.git::my-deploy@0.2.0",
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, rewritten)
+	require.Equal(t, 5, count)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
+)
+
+var (
+	auditStepYMLPath = ""
+	isAuditStrict    = false
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit a step.yml",
+	Long: `Audit a step.yml, the same way as it's audited before sharing,
+and with additional lint rules:
+- missing source_code_url, source.git which is not an http(s) .git URL
+- missing input summaries
+- unknown type_tags
+- sensitive inputs with default value
+- default values which are not one of the value_options
+
+Every finding is reported at once. The command fails if there's any error,
+or with --strict if there's any warning.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		silenceCheckFailure(cmd)
+		return auditStepYML(auditStepYMLPath, isAuditStrict)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(auditCmd)
+	auditCmd.Flags().StringVar(&auditStepYMLPath, "step-yml", "step.yml", "step.yml to audit")
+	auditCmd.Flags().BoolVar(&isAuditStrict, "strict", false, "Fail on warnings too")
+}
+
+func auditStepYML(pth string, isStrict bool) error {
+	fmt.Println(colorstring.Yellow("Auditing step.yml:"), pth)
+
+	findings, err := audit.StepYML(pth)
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range findings {
+		switch finding.Severity {
+		case audit.SeverityError:
+			fmt.Println(" *", colorstring.Red("[error]"), finding)
+		default:
+			fmt.Println(" *", colorstring.Yellow("[warning]"), finding)
+		}
+	}
+
+	errorCount, warningCount := audit.Count(findings)
+	if errorCount == 0 && warningCount == 0 {
+		fmt.Println(" *", colorstring.Green("[OK]"), "no issues found")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+	fmt.Println()
+	if errorCount > 0 || (isStrict && warningCount > 0) {
+		return checkFailedError{check: "audit"}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
+	"github.com/bitrise-io/bitrise-plugins-step/bitriseyml"
+)
+
+var (
+	checkConfigFormat   = ""
+	isCheckConfigStrict = false
+)
+
+// checkConfigCmd represents the check-config command
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config [bitrise.yml]",
+	Short: "Check the step inputs of a bitrise.yml",
+	Long: `Check the inputs of every StepLib step reference of a bitrise.yml (bitrise.yml in the current directory
+if not specified) against the definition of the referenced step version:
+- inputs which are not defined by the step, e.g. typos in the input keys (error)
+- values which are not one of the value_options of the input (error)
+- required inputs without value: not set in the workflow and without default value (error)
+- is_dont_change_value inputs which are set to a value other than their default (warning)
+
+Values referencing env vars (e.g. $MY_VALUE) are not checked against the value_options.
+Local, git and StepLib independent steps are not checked.
+The command fails if there's any error, or with --strict if there's any warning.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		silenceCheckFailure(cmd)
+		configPth := "bitrise.yml"
+		if len(args) > 0 {
+			configPth = args[0]
+		}
+		return checkConfigInputs(configPth)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(checkConfigCmd)
+	checkConfigCmd.Flags().StringVar(&checkConfigFormat, "format", "", "Output format. Accepted: raw, json.")
+	checkConfigCmd.Flags().BoolVar(&isCheckConfigStrict, "strict", false, "Fail on warnings too")
+}
+
+func checkConfigInputs(configPth string) error {
+	switch checkConfigFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", checkConfigFormat)
+	}
+
+	config, err := bitriseyml.ReadConfig(configPth)
+	if err != nil {
+		return err
+	}
+	references, err := bitriseyml.StepReferences(config)
+	if err != nil {
+		return err
+	}
+	collections, err := readReferencedCollections(references)
+	if err != nil {
+		return err
+	}
+
+	issues := []bitriseyml.InputIssueModel{}
+	var findings []audit.FindingModel
+	for _, reference := range references {
+		referenceIssues, err := bitriseyml.CheckReferenceInputs(reference, collections[reference.StepLib])
+		if err != nil {
+			return err
+		}
+		for _, issue := range referenceIssues {
+			issues = append(issues, issue)
+			findings = append(findings, audit.FindingModel{
+				Severity: issue.Severity,
+				Subject:  fmt.Sprintf("%s #%d %s", issue.Workflow, issue.Index+1, issue.Composite),
+				Message:  issue.String(),
+			})
+		}
+	}
+	errorCount, warningCount := audit.Count(findings)
+
+	if checkConfigFormat == output.FormatJSON {
+		bytes, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize issues, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+	} else {
+		fmt.Println(colorstring.Yellow("Checking step inputs of:"), configPth)
+		for _, finding := range findings {
+			switch finding.Severity {
+			case audit.SeverityError:
+				fmt.Println(" *", colorstring.Red("[error]"), finding)
+			default:
+				fmt.Println(" *", colorstring.Yellow("[warning]"), finding)
+			}
+		}
+		if errorCount == 0 && warningCount == 0 {
+			fmt.Println(" *", colorstring.Green("[OK]"), fmt.Sprintf("no issues found in %d step reference(s)", len(references)))
+		} else {
+			fmt.Println()
+			fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+		}
+		fmt.Println()
+	}
+
+	if errorCount > 0 || (isCheckConfigStrict && warningCount > 0) {
+		return checkFailedError{check: "config check"}
+	}
+	return nil
+}
@@ -5,16 +5,52 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	createAnswers         = create.AnswersModel{}
+	createAnswersFilePath = ""
+	isCreateNoInput       = false
+)
+
 // createCmd represents the create command
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new Step",
-	Long:  `Answer a couple of questions and have a fully working step in seconds!`,
+	Long: `Answer a couple of questions and have a fully working step in seconds!
+
+The answers can also be provided up front, with flags or with an answers file (--from-file),
+in which case only the missing values are asked.
+An answers file is a YAML file with the keys:
+author, title, id, summary, description, type_tag, toolkit, source_url, support_url, go_package_id, dir.
+Flags take precedence over the values of the answers file.
+
+Use --no-input to never ask anything, e.g. in CI: every missing value is reported at once.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return create.Step()
+		answers := createAnswers
+		if createAnswersFilePath != "" {
+			fileAnswers, err := create.ReadAnswersFile(createAnswersFilePath)
+			if err != nil {
+				return err
+			}
+			answers = fileAnswers.Merge(createAnswers)
+		}
+
+		return create.Step(answers, isCreateNoInput)
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(createCmd)
+	createCmd.Flags().StringVar(&createAnswers.Author, "author", "", "Author of the step")
+	createCmd.Flags().StringVar(&createAnswers.Title, "title", "", "Title / name of the step")
+	createCmd.Flags().StringVar(&createAnswers.ID, "id", "", "ID of the step - if not specified it's generated from the title")
+	createCmd.Flags().StringVar(&createAnswers.Summary, "summary", "", "Summary of the step")
+	createCmd.Flags().StringVar(&createAnswers.Description, "description", "", "Description of the step")
+	createCmd.Flags().StringVar(&createAnswers.TypeTag, "type-tag", "", "Primary category (type tag) of the step")
+	createCmd.Flags().StringVar(&createAnswers.Toolkit, "toolkit", "", "Toolkit (language) of the step")
+	createCmd.Flags().StringVar(&createAnswers.SourceURL, "source-url", "", "Website / source code (repository) URL of the step")
+	createCmd.Flags().StringVar(&createAnswers.SupportURL, "support-url", "", "Support URL of the step - if not specified it's derived from the source URL")
+	createCmd.Flags().StringVar(&createAnswers.GoPackageID, "go-package-id", "", "Go package ID, for the go toolkit - if not specified it's derived from the source URL")
+	createCmd.Flags().StringVar(&createAnswers.Dir, "dir", "", "Directory where the step should be created")
+	createCmd.Flags().StringVar(&createAnswersFilePath, "from-file", "", "Answers file (YAML) to read the answers from")
+	createCmd.Flags().BoolVar(&isCreateNoInput, "no-input", false, "Do not ask anything, fail if a required value is missing")
 }
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steplist"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	deprecatedCollection    = ""
+	deprecatedRemovalWithin = 0
+	deprecatedFormat        = ""
+)
+
+// deprecatedCmd represents the deprecated command
+var deprecatedCmd = &cobra.Command{
+	Use:   "deprecated",
+	Short: "List the deprecated steps of a collection",
+	Long: `List the deprecated and the soon to be removed steps of a collection,
+the ones to be removed soonest first.
+
+Use --removal-within to only list the steps which are removed (or already removed) within the given number of days.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printDeprecatedSteps(stepmanutil.CollectionOrDefault(deprecatedCollection))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(deprecatedCmd)
+	deprecatedCmd.Flags().StringVarP(&deprecatedCollection, "collection", "c", "", "Collection (StepLib) - if not specified the "+stepmanutil.CollectionEnvKey+" env or the official Bitrise StepLib is used")
+	deprecatedCmd.Flags().IntVar(&deprecatedRemovalWithin, "removal-within", 0, "Only list the steps which are removed within the given number of days")
+	deprecatedCmd.Flags().StringVar(&deprecatedFormat, "format", "", "Output format. Accepted: raw, json.")
+}
+
+// deprecatedStepModel is a deprecated step, with the days left until its removal
+type deprecatedStepModel struct {
+	steplist.ItemModel
+	// DaysUntilRemoval is negative if the step is already removed, nil if there's no removal date
+	DaysUntilRemoval *int `json:"days_until_removal,omitempty"`
+}
+
+// deprecatedSteps returns the deprecated steps, the ones to be removed soonest first.
+// If removalWithin is greater than 0, only the steps to be removed within that many days are returned.
+func deprecatedSteps(items []steplist.ItemModel, now time.Time, removalWithin int) []deprecatedStepModel {
+	var deprecated []deprecatedStepModel
+	for _, item := range items {
+		if !item.IsDeprecated {
+			continue
+		}
+
+		step := deprecatedStepModel{ItemModel: item}
+		if removal, ok := item.RemovalTime(); ok {
+			days := int(math.Ceil(removal.Sub(now).Hours() / 24))
+			step.DaysUntilRemoval = &days
+		}
+
+		if removalWithin > 0 && (step.DaysUntilRemoval == nil || *step.DaysUntilRemoval > removalWithin) {
+			continue
+		}
+		deprecated = append(deprecated, step)
+	}
+
+	sort.SliceStable(deprecated, func(i, j int) bool {
+		di, dj := deprecated[i].DaysUntilRemoval, deprecated[j].DaysUntilRemoval
+		if (di == nil) != (dj == nil) {
+			return di != nil
+		}
+		if di != nil && *di != *dj {
+			return *di < *dj
+		}
+		return deprecated[i].ID < deprecated[j].ID
+	})
+	return deprecated
+}
+
+func removalStatus(step deprecatedStepModel) string {
+	if step.DaysUntilRemoval == nil {
+		return "-"
+	}
+	days := *step.DaysUntilRemoval
+	switch {
+	case days <= 0:
+		return "removed"
+	case days == 1:
+		return "in 1 day"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+func printDeprecatedSteps(collectionID string) error {
+	switch deprecatedFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", deprecatedFormat)
+	}
+
+	if err := stepmanutil.EnsureCollectionIsSetUp(collectionID); err != nil {
+		return err
+	}
+	index, err := stepmanutil.ReadStepLibIndex(collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to read step lib (%s), err: %s", collectionID, err)
+	}
+
+	deprecated := deprecatedSteps(steplist.Items(collectionID, index.Collection, steplist.FilterModel{}), time.Now(), deprecatedRemovalWithin)
+
+	if deprecatedFormat == output.FormatJSON {
+		if deprecated == nil {
+			deprecated = []deprecatedStepModel{}
+		}
+		bytes, err := json.MarshalIndent(deprecated, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize deprecated steps, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	fmt.Println(colorstring.Yellow("Deprecated steps in:"), collectionID)
+	fmt.Println()
+	if len(deprecated) == 0 {
+		fmt.Println("No deprecated steps found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tLATEST\tREMOVAL DATE\tREMOVAL\tNOTES\t")
+	for _, step := range deprecated {
+		removalDate := step.RemovalDate
+		if removalDate == "" {
+			removalDate = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", step.ID, step.LatestVersion, removalDate, removalStatus(step), firstLine(step.DeprecateNotes))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steplist"
+)
+
+func Test_deprecatedSteps(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	items := []steplist.ItemModel{
+		{ID: "healthy"},
+		{ID: "no-removal-date", IsDeprecated: true, DeprecateNotes: "Use another step"},
+		{ID: "removed-soon", IsDeprecated: true, RemovalDate: "2024-06-10"},
+		{ID: "removed-later", IsDeprecated: true, RemovalDate: "2024-12-01"},
+		{ID: "already-removed", IsDeprecated: true, RemovalDate: "2024-01-01"},
+	}
+
+	ids := func(steps []deprecatedStepModel) []string {
+		var ids []string
+		for _, step := range steps {
+			ids = append(ids, step.ID)
+		}
+		return ids
+	}
+
+	t.Log("every deprecated step, the ones to be removed soonest first")
+	{
+		deprecated := deprecatedSteps(items, now, 0)
+		require.Equal(t, []string{"already-removed", "removed-soon", "removed-later", "no-removal-date"}, ids(deprecated))
+		require.Equal(t, "removed", removalStatus(deprecated[0]))
+		require.Equal(t, "in 9 days", removalStatus(deprecated[1]))
+		require.Equal(t, "-", removalStatus(deprecated[3]))
+	}
+
+	t.Log("removal within")
+	{
+		require.Equal(t, []string{"already-removed", "removed-soon"}, ids(deprecatedSteps(items, now, 30)))
+	}
+}
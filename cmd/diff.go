@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepdiff"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	diffCollection  = ""
+	diffStepYMLPath = ""
+	diffFormat      = ""
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <step-id> <from-version> <to-version>",
+	Short: "Compare two versions of a step",
+	Long: `Compare two versions of a step and list the changes of
+the inputs, outputs, default values, value options, toolkit, deps and run conditions.
+
+Breaking changes, which might require changing the workflows using the step, are flagged:
+removed inputs and outputs, new required inputs without default value and removed value options.
+
+The versions can be major or minor locked versions (e.g. 8 or 8.1.x), as in a bitrise.yml.
+
+With --step-yml the given step.yml is compared to a version of the step in the library:
+  step diff <step-id> [<from-version>] --step-yml step.yml
+If the version is not specified the latest version is used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("no step ID specified as a parameter")
+		}
+		stepID := args[0]
+		versions := args[1:]
+
+		if diffStepYMLPath != "" {
+			if len(versions) > 1 {
+				return fmt.Errorf("only the version to compare the step.yml to can be specified, got: %s", versions)
+			}
+			fromVersion := ""
+			if len(versions) == 1 {
+				fromVersion = versions[0]
+			}
+			return printStepYMLDiff(stepmanutil.CollectionOrDefault(diffCollection), stepID, fromVersion, diffStepYMLPath)
+		}
+
+		if len(versions) != 2 {
+			return fmt.Errorf("two versions have to be specified to compare, got: %s", versions)
+		}
+		return printStepVersionsDiff(stepmanutil.CollectionOrDefault(diffCollection), stepID, versions[0], versions[1])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&diffCollection, "collection", "c", "", "Collection (StepLib) of the step - if not specified the "+stepmanutil.CollectionEnvKey+" env or the official Bitrise StepLib is used")
+	diffCmd.Flags().StringVar(&diffStepYMLPath, "step-yml", "", "step.yml - if specified it's compared to the given (or the latest) version of the step in the library")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "", "Output format. Accepted: raw, json.")
+}
+
+// stepDiffModel is the json output of the diff command
+type stepDiffModel struct {
+	ID          string                 `json:"id"`
+	From        string                 `json:"from"`
+	To          string                 `json:"to"`
+	HasBreaking bool                   `json:"has_breaking_change"`
+	Changes     []stepdiff.ChangeModel `json:"changes"`
+}
+
+func printStepVersionsDiff(collectionID, stepID, fromVersion, toVersion string) error {
+	collection, err := readCollectionForDiff(collectionID)
+	if err != nil {
+		return err
+	}
+
+	from, fromResolution, err := stepmanutil.StepVersion(collection, stepID, fromVersion)
+	if err != nil {
+		return err
+	}
+	to, toResolution, err := stepmanutil.StepVersion(collection, stepID, toVersion)
+	if err != nil {
+		return err
+	}
+
+	return printStepDiff(stepID, fromResolution.Version, toResolution.Version, from, to)
+}
+
+func printStepYMLDiff(collectionID, stepID, fromVersion, ymlPth string) error {
+	collection, err := readCollectionForDiff(collectionID)
+	if err != nil {
+		return err
+	}
+
+	from, fromResolution, err := stepmanutil.StepVersion(collection, stepID, fromVersion)
+	if err != nil {
+		return err
+	}
+
+	to, err := stepman.ParseStepDefinition(ymlPth, false)
+	if err != nil {
+		return fmt.Errorf("failed to parse step.yml (path: %s), error: %s", ymlPth, err)
+	}
+
+	return printStepDiff(stepID, fromResolution.Version, ymlPth, from, to)
+}
+
+func readCollectionForDiff(collectionID string) (models.StepCollectionModel, error) {
+	switch diffFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return models.StepCollectionModel{}, fmt.Errorf("invalid format: %s", diffFormat)
+	}
+
+	if err := stepmanutil.EnsureCollectionIsSetUp(collectionID); err != nil {
+		return models.StepCollectionModel{}, err
+	}
+
+	collection, err := stepmanutil.ReadStepCollectionModel(collectionID)
+	if err != nil {
+		return models.StepCollectionModel{}, fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+	}
+	return collection, nil
+}
+
+func printStepDiff(stepID, fromName, toName string, from, to models.StepModel) error {
+	changes, err := stepdiff.Steps(from, to)
+	if err != nil {
+		return err
+	}
+
+	if diffFormat == output.FormatJSON {
+		diff := stepDiffModel{
+			ID:          stepID,
+			From:        fromName,
+			To:          toName,
+			HasBreaking: stepdiff.HasBreakingChange(changes),
+			Changes:     changes,
+		}
+		if diff.Changes == nil {
+			diff.Changes = []stepdiff.ChangeModel{}
+		}
+
+		bytes, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize step diff, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	fmt.Println(colorstring.Green(stepID) + ": " + fromName + " -> " + toName)
+	fmt.Println()
+
+	if len(changes) == 0 {
+		fmt.Println(" *", colorstring.Green("[OK]"), "no changes")
+		fmt.Println()
+		return nil
+	}
+
+	printChanges(changes)
+	fmt.Println()
+	return nil
+}
+
+// printChanges prints the breaking changes first, then the rest of the changes.
+func printChanges(changes []stepdiff.ChangeModel) {
+	for _, isBreaking := range []bool{true, false} {
+		for _, change := range changes {
+			if change.IsBreaking != isBreaking {
+				continue
+			}
+
+			kind := "[" + string(change.Kind) + "]"
+			if change.IsBreaking {
+				fmt.Println(" *", colorstring.Red("[breaking]"), colorstring.Yellow(kind), change)
+			} else {
+				fmt.Println(" *", colorstring.Yellow(kind), change)
+			}
+		}
+	}
+}
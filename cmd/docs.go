@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/docs"
+)
+
+var (
+	docsStepYMLPath = ""
+	docsWritePath   = ""
+	docsStepID      = ""
+	docsStepVersion = ""
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate the README documentation of a step",
+	Long: `Generate the README documentation of a step from its step.yml:
+title, summary, description, inputs, outputs, an example bitrise.yml usage,
+the toolkit and the dependencies of the step.
+
+The documentation is printed to the standard output, or with --write it's written into the README,
+between the ` + docs.StartMarker + ` and ` + docs.EndMarker + ` comments.
+The rest of the README is kept as it is. If the README has no markers yet, the documentation
+is appended to its end, between the markers.
+
+The step ID of the example is the name of the step.yml's directory (without the bitrise-step- or steps- prefix),
+if not specified with --id.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateStepDocs(docsStepYMLPath, docsWritePath)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(docsCmd)
+	docsCmd.Flags().StringVar(&docsStepYMLPath, "step-yml", "step.yml", "step.yml of the step")
+	docsCmd.Flags().StringVar(&docsWritePath, "write", "", "README to write the documentation into (e.g. README.md)")
+	docsCmd.Flags().StringVar(&docsStepID, "id", "", "ID of the step, used in the example usage")
+	docsCmd.Flags().StringVar(&docsStepVersion, "version", "", "Version of the step, its major version is used in the example usage")
+}
+
+// stepIDFromStepYMLPath returns the name of the step.yml's directory, without the step repository prefixes.
+func stepIDFromStepYMLPath(pth string) (string, error) {
+	absPth, err := filepath.Abs(pth)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(filepath.Dir(absPth))
+	for _, prefix := range []string{"bitrise-step-", "steps-"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name, nil
+}
+
+func generateStepDocs(ymlPth, readmePth string) error {
+	step, err := stepman.ParseStepDefinition(ymlPth, false)
+	if err != nil {
+		return fmt.Errorf("failed to parse step.yml (path: %s), error: %s", ymlPth, err)
+	}
+
+	stepID := docsStepID
+	if stepID == "" {
+		if stepID, err = stepIDFromStepYMLPath(ymlPth); err != nil {
+			return fmt.Errorf("failed to get step ID, err: %s", err)
+		}
+	}
+
+	docsModel, err := docs.NewDocsModel(step, stepID, docsStepVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read step.yml (path: %s), error: %s", ymlPth, err)
+	}
+	content, err := docs.Render(docsModel)
+	if err != nil {
+		return fmt.Errorf("failed to generate docs, err: %s", err)
+	}
+
+	if readmePth == "" {
+		fmt.Print(content)
+		return nil
+	}
+
+	readme := ""
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(readmePth); err == nil {
+		bytes, err := os.ReadFile(readmePth)
+		if err != nil {
+			return fmt.Errorf("failed to read README (%s), err: %s", readmePth, err)
+		}
+		readme = string(bytes)
+		mode = info.Mode()
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read README (%s), err: %s", readmePth, err)
+	}
+
+	newReadme, err := docs.ReplaceSection(readme, content)
+	if err != nil {
+		return fmt.Errorf("failed to update README (%s), err: %s", readmePth, err)
+	}
+	if newReadme == readme {
+		fmt.Println(colorstring.Green("README is up to date:"), readmePth)
+		return nil
+	}
+	if err := os.WriteFile(readmePth, []byte(newReadme), mode); err != nil {
+		return fmt.Errorf("failed to write README (%s), err: %s", readmePth, err)
+	}
+	fmt.Println(colorstring.Green("README updated:"), readmePth)
+	return nil
+}
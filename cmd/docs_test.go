@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepIDFromStepYMLPath(t *testing.T) {
+	for pth, want := range map[string]string{
+		"/src/bitrise-step-my-step/step.yml": "my-step",
+		"/src/steps-git-clone/step.yml":      "git-clone",
+		"/src/script/step.yml":               "script",
+	} {
+		got, err := stepIDFromStepYMLPath(filepath.FromSlash(pth))
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
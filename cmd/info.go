@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/colorstring"
@@ -13,12 +15,23 @@ import (
 	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
 	"github.com/bitrise-io/bitrise-plugins-step/utils"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// localCollectionUsage is appended to the usage of the --collection flags which accept a local StepLib
+const localCollectionUsage = ". A local StepLib directory or file:// URI is read directly (the steps/<id>/<version>/step.yml files), without setting it up with stepman"
+
+const (
+	outputFormatMarkdown = "markdown"
+	outputFormatJSON     = "json"
+	outputFormatYAML     = "yaml"
 )
 
 var (
-	stepVersion  = ""
-	stepYMLPath  = ""
-	outputFormat = ""
+	stepVersion    = ""
+	stepYMLPath    = ""
+	outputFormat   = ""
+	infoCollection = ""
 )
 
 // infoCmd represents the info command
@@ -41,7 +54,7 @@ var infoCmd = &cobra.Command{
 		}
 		stepID := args[0]
 
-		return printStepInfoFromLibrary(stepID)
+		return printStepInfoFromLibrary(stepmanutil.CollectionOrDefault(infoCollection), stepID)
 	},
 }
 
@@ -51,93 +64,89 @@ func printStepInfoFromStepYML(ymlPth string) error {
 		return fmt.Errorf("failed to parse step.yml (path: %s), error: %s", ymlPth, err)
 	}
 
-	// inputs, err := getEnvInfos(step.Inputs)
-	// if err != nil {
-	// 	return fmt.Errorf("failed to get step input infos, err: %s", err)
-	// }
-
-	// outputs, err := getEnvInfos(step.Outputs)
-	// if err != nil {
-	// 	return fmt.Errorf("Failed to get step output infos, err: %s", err)
-	// }
-
 	stepInfo := models.StepInfoModel{
 		Library:       "",
 		ID:            "step.yml:" + ymlPth,
 		Version:       "",
 		LatestVersion: "",
-		Step: models.StepModel{
-			Description:   step.Description,
-			SourceCodeURL: step.SourceCodeURL,
-			SupportURL:    step.SupportURL,
-			Inputs:        step.Inputs,
-			Outputs:       step.Outputs,
-		},
+		Step:          step,
+		DefinitionPth: ymlPth,
 	}
 
-	return printStepVersionInfoOutput(stepInfo)
+	return printStepVersionInfoOutput(stepInfo, stepmanutil.VersionResolutionModel{})
 }
 
-func printStepInfoFromLibrary(stepID string) error {
-	collectionID := "https://github.com/bitrise-io/bitrise-steplib.git"
-	_, stepVersion, err := stepmanutil.ReadStepVersionInfo(collectionID, stepID, stepVersion)
-
+func printStepInfoFromLibrary(collectionID, stepID string) error {
+	stepInfo, resolution, err := readStepInfoFromLibrary(collectionID, stepID, stepVersion)
 	if err != nil {
-		return fmt.Errorf("failed to get step info: %s", err)
+		return err
 	}
+	return printStepVersionInfoOutput(stepInfo, resolution)
+}
 
-	step, err := stepman.ReadStepVersionInfo(collectionID, stepID, stepVersion)
-	if err != nil {
-		return fmt.Errorf("failed to read step version info: %s", err)
+// readStepInfoFromLibrary reads the step version from the collection, the version can be a version constraint,
+// the latest version is read if it's empty.
+func readStepInfoFromLibrary(collectionID, stepID, version string) (models.StepInfoModel, stepmanutil.VersionResolutionModel, error) {
+	if err := stepmanutil.EnsureCollectionIsSetUp(collectionID); err != nil {
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, err
 	}
 
-	collection, err := stepman.ReadStepSpec(collectionID)
+	index, err := stepmanutil.ReadStepLibIndex(collectionID)
 	if err != nil {
-		return fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
 	}
 
-	latestStepVersion, err := collection.GetLatestStepVersion(stepID)
+	resolution, err := index.ResolveStepVersion(stepID, version)
 	if err != nil {
-		return fmt.Errorf("failed to get latest version of step (id:%s)", stepID)
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to get step info: %s", err)
 	}
 
-	if stepVersion == "" {
-		stepVersion = latestStepVersion
+	latestStepVersion, err := index.Collection.GetLatestStepVersion(stepID)
+	if err != nil {
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to get latest version of step (id:%s)", stepID)
 	}
 
-	// inputs, err := getEnvInfos(step.Inputs)
-	// if err != nil {
-	// 	return fmt.Errorf("Failed to get step (id:%s) input infos, err: %s", stepID, err)
-	// }
+	// the spec.json contains the step definitions as they are in the step.yml files,
+	// the same defaults are filled as for a parsed step.yml, to print both the same way.
+	// The index only contains the latest version of the steps, the full spec.json is only read for earlier versions.
+	var step models.StepModel
+	if resolution.Version == latestStepVersion {
+		if step, err = index.LatestStepVersion(stepID); err != nil {
+			return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to get step info: %s", err)
+		}
+	} else {
+		collection, err := stepmanutil.ReadStepCollectionModel(collectionID)
+		if err != nil {
+			return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+		}
+		if step, _, err = stepmanutil.StepVersion(collection, stepID, resolution.Version); err != nil {
+			return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to get step info: %s", err)
+		}
+	}
 
-	// outputs, err := getEnvInfos(step.Outputs)
-	// if err != nil {
-	// 	return fmt.Errorf("Failed to get step (id:%s) output infos, err: %s", stepID, err)
-	// }
+	stepLib, found, err := stepmanutil.ReadStepLib(collectionID)
+	if err != nil {
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+	} else if !found {
+		return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("no route found for collection: %s", collectionID)
+	}
 
 	stepInfo := models.StepInfoModel{
 		Library:       collectionID,
 		ID:            stepID,
-		Version:       stepVersion,
+		Version:       resolution.Version,
 		LatestVersion: latestStepVersion,
-		Step: models.StepModel{
-			Description:   step.Step.Description,
-			SourceCodeURL: step.Step.SourceCodeURL,
-			SupportURL:    step.Step.SupportURL,
-			Inputs:        step.Step.Inputs,
-			Outputs:       step.Step.Outputs,
-		},
+		Step:          step,
+		DefinitionPth: stepLib.StepDefinitionPath(stepID, resolution.Version),
 	}
-
-	route, found := stepman.ReadRoute(collectionID)
-	if !found {
-		return fmt.Errorf("no route found for collection: %s", collectionID)
+	if resolution.IsConstraint() {
+		stepInfo.OriginalVersion = resolution.Constraint
 	}
-	globalStepInfoPth := stepman.GetStepGlobalInfoPath(route, stepID)
+	globalStepInfoPth := stepLib.StepGroupInfoPath(stepID)
 	if globalStepInfoPth != "" {
 		globalInfo, found, err := stepman.ParseStepGroupInfoModel(globalStepInfoPth)
 		if err != nil {
-			return fmt.Errorf("failed to get step (path:%s) output infos, err: %s", globalStepInfoPth, err)
+			return models.StepInfoModel{}, stepmanutil.VersionResolutionModel{}, fmt.Errorf("failed to get step (path:%s) output infos, err: %s", globalStepInfoPth, err)
 		}
 
 		if found {
@@ -145,52 +154,328 @@ func printStepInfoFromLibrary(stepID string) error {
 		}
 	}
 
-	return printStepVersionInfoOutput(stepInfo)
+	return stepInfo, resolution, nil
 }
 
 func init() {
 	RootCmd.AddCommand(infoCmd)
-	infoCmd.Flags().StringVarP(&stepVersion, "version", "v", "", "Version - if not specified will print info about the latest version")
+	infoCmd.Flags().StringVarP(&stepVersion, "version", "v", "", "Version - if not specified will print info about the latest version.\nCan also be a major or minor locked version (e.g. 8, 8.1 or 8.1.x), as in a bitrise.yml")
+	infoCmd.Flags().StringVarP(&infoCollection, "collection", "c", "", "Collection (StepLib) of the step - if not specified the "+stepmanutil.CollectionEnvKey+" env or the official Bitrise StepLib is used"+localCollectionUsage)
 	infoCmd.Flags().StringVar(&stepYMLPath, "step-yml", "", "step.yml - if specified infos will be printed from the specified step.yml, not from a library")
-	infoCmd.Flags().StringVar(&outputFormat, "output-format", "", `Output format. Default is "rich command line", but can also be "markdown", to generate a standard markdown output instead.`)
+	infoCmd.Flags().StringVar(&outputFormat, "output-format", "", `Output format. Default is "rich command line", but can also be "markdown", to generate a standard markdown output instead,
+or "json" / "yaml", to serialize the full step info.`)
 }
 
-func getEnvInfos(envs []envmanModels.EnvironmentItemModel) ([]models.EnvInfoModel, error) {
-	envInfos := []models.EnvInfoModel{}
+// envInfoModel holds every option of a step input or output, with the defaults filled.
+type envInfoModel struct {
+	Key               string
+	DefaultValue      string
+	Title             string
+	Summary           string
+	Description       string
+	Category          string
+	ValueOptions      []string
+	IsRequired        bool
+	IsSensitive       bool
+	IsExpand          bool
+	IsDontChangeValue bool
+	IsTemplate        bool
+	SkipIfEmpty       bool
+}
+
+// envCategoryModel is a group of inputs or outputs with the same category.
+type envCategoryModel struct {
+	Name string
+	Envs []envInfoModel
+}
+
+func getEnvInfos(envs []envmanModels.EnvironmentItemModel) ([]envInfoModel, error) {
+	envInfos := []envInfoModel{}
 	for _, env := range envs {
 		key, value, err := env.GetKeyValuePair()
 		if err != nil {
-			return []models.EnvInfoModel{}, err
+			return []envInfoModel{}, err
 		}
 
 		options, err := env.GetOptions()
 		if err != nil {
-			return []models.EnvInfoModel{}, err
+			return []envInfoModel{}, err
 		}
 
-		envInfo := models.EnvInfoModel{
-			Key:          key,
-			Description:  *options.Description,
-			ValueOptions: options.ValueOptions,
-			DefaultValue: value,
-			IsExpand:     *options.IsExpand,
+		envInfo := envInfoModel{
+			Key:               key,
+			DefaultValue:      value,
+			Title:             pointers.String(options.Title),
+			Summary:           pointers.String(options.Summary),
+			Description:       pointers.String(options.Description),
+			Category:          pointers.String(options.Category),
+			ValueOptions:      options.ValueOptions,
+			IsRequired:        boolOption(options.IsRequired, envmanModels.DefaultIsRequired),
+			IsSensitive:       boolOption(options.IsSensitive, envmanModels.DefaultIsSensitive),
+			IsExpand:          boolOption(options.IsExpand, envmanModels.DefaultIsExpand),
+			IsDontChangeValue: boolOption(options.IsDontChangeValue, envmanModels.DefaultIsDontChangeValue),
+			IsTemplate:        boolOption(options.IsTemplate, envmanModels.DefaultIsTemplate),
+			SkipIfEmpty:       boolOption(options.SkipIfEmpty, envmanModels.DefaultSkipIfEmpty),
 		}
 		envInfos = append(envInfos, envInfo)
 	}
 	return envInfos, nil
 }
 
-func printStepVersionInfoOutput(stepVersionInfo models.StepInfoModel) error {
-	isMarkdown := (outputFormat == "markdown")
+func boolOption(option *bool, defaultValue bool) bool {
+	if option == nil {
+		return defaultValue
+	}
+	return *option
+}
+
+// groupEnvInfosByCategory groups the envs by category, in the order of the
+// first occurrence of each category. Envs without category come first.
+func groupEnvInfosByCategory(envInfos []envInfoModel) []envCategoryModel {
+	categories := []envCategoryModel{{Name: ""}}
+	categoryIdx := map[string]int{"": 0}
+	for _, envInfo := range envInfos {
+		idx, found := categoryIdx[envInfo.Category]
+		if !found {
+			idx = len(categories)
+			categoryIdx[envInfo.Category] = idx
+			categories = append(categories, envCategoryModel{Name: envInfo.Category})
+		}
+		categories[idx].Envs = append(categories[idx].Envs, envInfo)
+	}
+
+	if len(categories[0].Envs) == 0 {
+		return categories[1:]
+	}
+	return categories
+}
+
+// envFlags returns the names of the env's options which differ from the default.
+func envFlags(envInfo envInfoModel) []string {
+	var flags []string
+	if envInfo.IsRequired {
+		flags = append(flags, "required")
+	}
+	if envInfo.IsSensitive {
+		flags = append(flags, "sensitive")
+	}
+	if !envInfo.IsExpand {
+		flags = append(flags, "not expanded")
+	}
+	if envInfo.IsDontChangeValue {
+		flags = append(flags, "don't change value")
+	}
+	if envInfo.IsTemplate {
+		flags = append(flags, "template")
+	}
+	if envInfo.SkipIfEmpty {
+		flags = append(flags, "skip if empty")
+	}
+	return flags
+}
+
+func printEnvInfos(title string, envs []envmanModels.EnvironmentItemModel, isMarkdown bool) error {
+	if len(envs) == 0 {
+		return nil
+	}
+
+	envInfos, err := getEnvInfos(envs)
+	if err != nil {
+		return err
+	}
+
+	if isMarkdown {
+		fmt.Println()
+		fmt.Println("# " + title)
+	} else {
+		fmt.Println()
+		fmt.Println(colorstring.Blue("=== " + title + " =========="))
+	}
+
+	for _, category := range groupEnvInfosByCategory(envInfos) {
+		if category.Name != "" {
+			if isMarkdown {
+				fmt.Println()
+				fmt.Println("## " + category.Name)
+			} else {
+				fmt.Println()
+				fmt.Println(colorstring.Blue("--- " + category.Name + " ---"))
+			}
+		}
+
+		for _, envInfo := range category.Envs {
+			if isMarkdown {
+				printEnvInfoMarkdown(envInfo)
+			} else {
+				printEnvInfoRich(envInfo)
+			}
+		}
+	}
+	return nil
+}
+
+func printEnvInfoMarkdown(envInfo envInfoModel) {
+	fmt.Println()
+	fmt.Println("### `" + envInfo.Key + "`")
+	fmt.Println()
+	if envInfo.Title != "" {
+		fmt.Println("- Title: " + envInfo.Title)
+	}
+	if envInfo.Summary != "" {
+		fmt.Println("- Summary: " + envInfo.Summary)
+	}
+	if envInfo.DefaultValue != "" {
+		fmt.Println("- Default value: `" + envInfo.DefaultValue + "`")
+	}
+	if len(envInfo.ValueOptions) > 0 {
+		fmt.Println("- Value options: `" + strings.Join(envInfo.ValueOptions, "`, `") + "`")
+	}
+	fmt.Println("- Required: " + yesNo(envInfo.IsRequired))
+	fmt.Println("- Sensitive: " + yesNo(envInfo.IsSensitive))
+	fmt.Println("- Expand environment variables: " + yesNo(envInfo.IsExpand))
+	if envInfo.IsDontChangeValue {
+		fmt.Println("- Don't change value: yes")
+	}
+	if envInfo.IsTemplate {
+		fmt.Println("- Template: yes")
+	}
+	if envInfo.SkipIfEmpty {
+		fmt.Println("- Skip if empty: yes")
+	}
+	if envInfo.Description != "" {
+		fmt.Println()
+		fmt.Println("#### _Description_")
+		fmt.Println()
+		fmt.Println(envInfo.Description)
+	}
+}
+
+func printEnvInfoRich(envInfo envInfoModel) {
+	fmt.Println()
+	keyLine := colorstring.Green(envInfo.Key) + ":"
+	if flags := envFlags(envInfo); len(flags) > 0 {
+		keyLine += " (" + strings.Join(flags, ", ") + ")"
+	}
+	fmt.Println(keyLine)
+
+	if envInfo.Title != "" {
+		fmt.Println(colorstring.Yellow("  Title") + ": " + envInfo.Title)
+	}
+	if envInfo.Summary != "" {
+		fmt.Println(colorstring.Yellow("  Summary") + ": " + envInfo.Summary)
+	}
+	if envInfo.DefaultValue != "" {
+		fmt.Println(colorstring.Yellow("  Default") + ": " + envInfo.DefaultValue)
+	}
+	if len(envInfo.ValueOptions) > 0 {
+		fmt.Println(colorstring.Yellow("  Options") + ": " + strings.Join(envInfo.ValueOptions, ", "))
+	}
+	if envInfo.Description != "" {
+		fmt.Println(colorstring.Yellow("  Description") + ":")
+		fmt.Println(utils.IndentTextWithMaxLength(envInfo.Description, "  ", 80))
+	}
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// serializeStepInfo serializes the step info in the given (json or yaml) format.
+// Inputs and outputs without value are serialized with an empty string value.
+func serializeStepInfo(stepInfo models.StepInfoModel, format string) (string, error) {
+	stepInfo.Step.Inputs = envsWithStringValues(stepInfo.Step.Inputs)
+	stepInfo.Step.Outputs = envsWithStringValues(stepInfo.Step.Outputs)
+
+	var bytes []byte
+	var err error
+	if format == outputFormatJSON {
+		bytes, err = json.MarshalIndent(stepInfo, "", "  ")
+	} else {
+		bytes, err = yaml.Marshal(stepInfo)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize step info, err: %s", err)
+	}
+	return strings.TrimSuffix(string(bytes), "\n"), nil
+}
+
+func envsWithStringValues(envs []envmanModels.EnvironmentItemModel) []envmanModels.EnvironmentItemModel {
+	var converted []envmanModels.EnvironmentItemModel
+	for _, env := range envs {
+		convertedEnv := envmanModels.EnvironmentItemModel{}
+		for key, value := range env {
+			if value == nil {
+				value = ""
+			}
+			convertedEnv[key] = value
+		}
+		converted = append(converted, convertedEnv)
+	}
+	return converted
+}
+
+func printStepVersionInfoOutput(stepVersionInfo models.StepInfoModel, resolution stepmanutil.VersionResolutionModel) error {
+	switch outputFormat {
+	case "", outputFormatMarkdown:
+	case outputFormatJSON, outputFormatYAML:
+		out, err := serializeStepInfo(stepVersionInfo, outputFormat)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s", outputFormat)
+	}
+
+	isMarkdown := (outputFormat == outputFormatMarkdown)
 
 	// Step ID, collection, version, ...
 	if isMarkdown {
 		fmt.Println("# " + stepVersionInfo.ID)
 		fmt.Println()
 		fmt.Println("- version: " + stepVersionInfo.Version)
+		if resolution.IsConstraint() {
+			fmt.Println("- version constraint: " + resolution.Constraint)
+			if others := otherCandidates(resolution); len(others) > 0 {
+				fmt.Println("- other matching versions: " + strings.Join(others, ", "))
+			}
+		}
 		fmt.Println("- collection: " + stepVersionInfo.Library)
+		if stepVersionInfo.LatestVersion != "" {
+			fmt.Println("- latest version: " + stepVersionInfo.LatestVersion)
+		}
+		if stepVersionInfo.GroupInfo.Maintainer != "" {
+			fmt.Println("- maintainer: " + stepVersionInfo.GroupInfo.Maintainer)
+		}
+		if isDeprecated(stepVersionInfo.GroupInfo) {
+			fmt.Println()
+			fmt.Println("> **Deprecated**: " + deprecationSummary(stepVersionInfo.GroupInfo))
+		}
+		if isOutdatedVersion(stepVersionInfo) {
+			fmt.Println()
+			fmt.Println("> **Note**: this is not the latest version of the step, the latest version is " + stepVersionInfo.LatestVersion)
+		}
 	} else {
 		fmt.Println(colorstring.Green(stepVersionInfo.ID) + "  @" + stepVersionInfo.Version + "  [" + stepVersionInfo.Library + "]")
+		if resolution.IsConstraint() {
+			fmt.Println(colorstring.Yellow("Resolved") + ": " + resolution.Constraint + " -> " + resolution.Version)
+			if others := otherCandidates(resolution); len(others) > 0 {
+				fmt.Println(colorstring.Yellow("Other matching versions") + ": " + strings.Join(others, ", "))
+			}
+		}
+		if stepVersionInfo.GroupInfo.Maintainer != "" {
+			fmt.Println(colorstring.Yellow("Maintainer") + ": " + stepVersionInfo.GroupInfo.Maintainer)
+		}
+		if isDeprecated(stepVersionInfo.GroupInfo) {
+			fmt.Println(colorstring.Red("DEPRECATED") + ": " + deprecationSummary(stepVersionInfo.GroupInfo))
+		}
+		if isOutdatedVersion(stepVersionInfo) {
+			fmt.Println(colorstring.Yellow("[!] This is not the latest version of the step, the latest version is: " + stepVersionInfo.LatestVersion))
+		}
 		fmt.Println()
 	}
 	// base infos like support & source URL
@@ -216,41 +501,34 @@ func printStepVersionInfoOutput(stepVersionInfo models.StepInfoModel) error {
 		fmt.Println(utils.IndentTextWithMaxLength(pointers.String(stepVersionInfo.Step.Description), "", 80))
 	}
 
-	// inputs
-	if len(stepVersionInfo.Step.Inputs) > 0 {
-		if isMarkdown {
-			fmt.Println()
-			fmt.Println("# Inputs")
-		} else {
-			fmt.Println()
-			fmt.Println(colorstring.Blue("=== Inputs =========="))
-		}
+	// inputs & outputs
+	if err := printEnvInfos("Inputs", stepVersionInfo.Step.Inputs, isMarkdown); err != nil {
+		return fmt.Errorf("failed to get step input infos, err: %s", err)
+	}
+	if err := printEnvInfos("Outputs", stepVersionInfo.Step.Outputs, isMarkdown); err != nil {
+		return fmt.Errorf("failed to get step output infos, err: %s", err)
+	}
+	fmt.Println()
+	return nil
+}
 
-		inputs, err := getEnvInfos(stepVersionInfo.Step.Inputs)
-		if err != nil {
-			return fmt.Errorf("failed to get step input infos, err: %s", err)
+// otherCandidates returns the versions matching the constraint, except the resolved one.
+func otherCandidates(resolution stepmanutil.VersionResolutionModel) []string {
+	var others []string
+	for _, candidate := range resolution.Candidates {
+		if candidate != resolution.Version {
+			others = append(others, candidate)
 		}
+	}
+	return others
+}
 
-		for _, input := range inputs {
-			if isMarkdown {
-				fmt.Println()
-				fmt.Println("## `" + input.Key + "`")
-				fmt.Println()
-				fmt.Println("### _Description_")
-				fmt.Println()
-				fmt.Println(input.Description)
-			} else {
-				fmt.Println()
-				fmt.Println(colorstring.Green(input.Key) + ":")
+func isDeprecated(info models.StepGroupInfoModel) bool {
+	return info.DeprecateNotes != "" || info.RemovalDate != ""
+}
 
-				// DefaultValue string   `json:"default_value"`
-				// ValueOptions []string `json:"value_options"`
-				// IsExpand     bool     `json:"is_expand"`
-				fmt.Println(colorstring.Yellow("  Description") + ":")
-				fmt.Println(utils.IndentTextWithMaxLength(input.Description, "  ", 80))
-			}
-		}
-	}
-	fmt.Println()
-	return nil
+// isOutdatedVersion returns true if the step is from a library,
+// and the version is not the latest version of the step.
+func isOutdatedVersion(stepInfo models.StepInfoModel) bool {
+	return stepInfo.LatestVersion != "" && stepInfo.Version != stepInfo.LatestVersion
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_serializeStepInfo(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/step.yml", false)
+	require.NoError(t, err)
+	stepInfo := models.StepInfoModel{
+		ID:      "test",
+		Version: "1.0.0",
+		GroupInfo: models.StepGroupInfoModel{
+			DeprecateNotes: "Use another step",
+		},
+		Step: step,
+	}
+
+	t.Log("json")
+	{
+		out, err := serializeStepInfo(stepInfo, outputFormatJSON)
+		require.NoError(t, err)
+
+		var parsed map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &parsed))
+		require.Equal(t, "Use another step", parsed["info"].(map[string]interface{})["deprecate_notes"])
+
+		inputs := parsed["step"].(map[string]interface{})["inputs"].([]interface{})
+		require.Equal(t, 3, len(inputs))
+		apiToken := inputs[1].(map[string]interface{})
+		require.Equal(t, "", apiToken["api_token"])
+		require.Equal(t, true, apiToken["opts"].(map[string]interface{})["is_sensitive"])
+	}
+
+	t.Log("yaml")
+	{
+		out, err := serializeStepInfo(stepInfo, outputFormatYAML)
+		require.NoError(t, err)
+
+		var parsed models.StepInfoModel
+		require.NoError(t, yaml.Unmarshal([]byte(out), &parsed))
+		require.Equal(t, "test", parsed.ID)
+		require.Equal(t, "1.0.0", parsed.Version)
+		require.Equal(t, 3, len(parsed.Step.Inputs))
+		require.NotNil(t, parsed.Step.Toolkit.Bash)
+	}
+}
+
+func Test_groupEnvInfosByCategory(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/step.yml", false)
+	require.NoError(t, err)
+
+	envInfos, err := getEnvInfos(step.Inputs)
+	require.NoError(t, err)
+	require.Equal(t, envInfoModel{
+		Key:          "mode",
+		DefaultValue: "fast",
+		Title:        "Mode",
+		Summary:      "Mode of the test",
+		Category:     "Config",
+		ValueOptions: []string{"fast", "slow"},
+		IsRequired:   true,
+		IsExpand:     true,
+	}, envInfos[0])
+
+	categories := groupEnvInfosByCategory(envInfos)
+	require.Equal(t, 3, len(categories))
+	require.Equal(t, "", categories[0].Name)
+	require.Equal(t, "verbose", categories[0].Envs[0].Key)
+	require.Equal(t, "Config", categories[1].Name)
+	require.Equal(t, "mode", categories[1].Envs[0].Key)
+	require.Equal(t, "Auth", categories[2].Name)
+	require.Equal(t, "api_token", categories[2].Envs[0].Key)
+
+	require.Equal(t, []string{"required"}, envFlags(envInfos[0]))
+	require.Equal(t, []string{"sensitive"}, envFlags(envInfos[1]))
+}
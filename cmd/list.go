@@ -1,24 +1,49 @@
 package cmd
 
 import (
-	"errors"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/bitrise-io/bitrise/output"
-	"github.com/bitrise-io/bitrise/tools"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steplist"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+const (
+	listFormatTable = "table"
+	listFormatCSV   = "csv"
 )
 
 var (
 	collection = ""
 	format     = ""
+	listFilter = steplist.FilterModel{}
+	listSortBy = ""
 )
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List of available steps",
-	Long:  "List of available steps",
+	Long: `List the latest version of the available steps of a collection,
+with their title, type tags, maintainer and deprecation.
+
+If the collection is not specified, the steps of every collection which is set up are listed,
+with the collection each step comes from. A step with the same ID in multiple collections
+is listed once for each collection.
+
+The steps can be filtered by type tag, host OS and toolkit, and sorted by id, title,
+maintainer or the date the latest version was published at (most recent first).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return printStepList()
 	},
@@ -26,30 +51,185 @@ var listCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(listCmd)
-	listCmd.Flags().StringVarP(&collection, "collection", "c", "", "Collection of step.")
-	listCmd.Flags().StringVar(&format, "format", "", "Output format. Accepted: raw, json.")
+	listCmd.Flags().StringVarP(&collection, "collection", "c", "", "Collection of step - if not specified every collection which is set up is listed"+localCollectionUsage)
+	listCmd.Flags().StringVar(&format, "format", "", "Output format. Accepted: table (default), raw, json, yaml, csv.")
+	listCmd.Flags().StringVar(&listFilter.TypeTag, "type-tag", "", "Only list steps with the given type tag (e.g. deploy)")
+	listCmd.Flags().StringVar(&listFilter.HostOS, "host-os", "", "Only list steps which can run on the given host OS (e.g. osx or ubuntu)")
+	listCmd.Flags().StringVar(&listFilter.Toolkit, "toolkit", "", "Only list steps with the given toolkit (bash, go, swift or kotlin)")
+	listCmd.Flags().StringVar(&listSortBy, "sort", steplist.SortByID, "Sort by: "+strings.Join(steplist.SortKeys, ", "))
 }
 
 func printStepList() error {
-	if collection == "" {
-		return errors.New("no collection defined")
+	switch format {
+	case "", listFormatTable, output.FormatRaw, outputFormatJSON, outputFormatYAML, listFormatCSV:
+	default:
+		return fmt.Errorf("invalid format: %s", format)
 	}
+
+	collectionIDs, err := stepmanutil.CollectionIDs()
+	if err != nil {
+		return fmt.Errorf("failed to read the collections, err: %s", err)
+	}
+	if collection != "" {
+		if err := stepmanutil.EnsureCollectionIsSetUp(collection); err != nil {
+			return err
+		}
+		collectionIDs = []string{collection}
+	}
+	if len(collectionIDs) == 0 {
+		return fmt.Errorf("no collection is set up, you can set up the official Bitrise StepLib with: stepman setup --collection %s", stepmanutil.DefaultCollectionURI)
+	}
+
+	var items []steplist.ItemModel
+	for _, collectionID := range collectionIDs {
+		index, err := stepmanutil.ReadStepLibIndex(collectionID)
+		if err != nil {
+			return fmt.Errorf("failed to read step lib (%s), err: %s", collectionID, err)
+		}
+		items = append(items, steplist.Items(collectionID, index.Collection, listFilter)...)
+	}
+	if err := steplist.Sort(items, listSortBy); err != nil {
+		return err
+	}
+
+	out, err := formatStepList(items, format, len(collectionIDs) > 1)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// formatStepList formats the items, the table output only has a collection column
+// if isMultipleCollections is true.
+func formatStepList(items []steplist.ItemModel, format string, isMultipleCollections bool) (string, error) {
+	var b strings.Builder
+
 	switch format {
-	case "", output.FormatRaw:
-		out, err := tools.StepmanRawStepList(collection)
+	case output.FormatRaw:
+		for _, item := range items {
+			fmt.Fprintf(&b, " * %s\n", item.Title)
+			fmt.Fprintf(&b, "   ID: %s\n", item.ID)
+			fmt.Fprintf(&b, "   Collection: %s\n", item.Collection)
+			fmt.Fprintf(&b, "   Latest Version: %s\n", item.LatestVersion)
+			fmt.Fprintf(&b, "   Summary: %s\n", firstLine(item.Summary))
+			if item.IsDeprecated {
+				fmt.Fprintf(&b, "   Deprecated: %s\n", deprecationNotes(item))
+			}
+			fmt.Fprintln(&b)
+		}
+	case outputFormatJSON:
+		if items == nil {
+			items = []steplist.ItemModel{}
+		}
+		bytes, err := json.MarshalIndent(items, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to print step list, err: %s", err)
+			return "", fmt.Errorf("failed to serialize step list, err: %s", err)
 		}
-		fmt.Println("Step list:")
-		fmt.Println(out)
-	case output.FormatJSON:
-		out, err := tools.StepmanJSONStepList(collection)
+		b.Write(bytes)
+		b.WriteString("\n")
+	case outputFormatYAML:
+		if items == nil {
+			items = []steplist.ItemModel{}
+		}
+		bytes, err := yaml.Marshal(items)
 		if err != nil {
-			return fmt.Errorf("failed to print step list, err: %s", err)
+			return "", fmt.Errorf("failed to serialize step list, err: %s", err)
+		}
+		b.Write(bytes)
+	case listFormatCSV:
+		if err := writeStepListCSV(&b, items); err != nil {
+			return "", fmt.Errorf("failed to write step list csv, err: %s", err)
 		}
-		fmt.Println(out)
 	default:
-		return fmt.Errorf("invalid format: %s", format)
+		if len(items) == 0 {
+			return "No steps found\n", nil
+		}
+		if err := writeStepListTable(&b, items, isMultipleCollections); err != nil {
+			return "", fmt.Errorf("failed to write step list table, err: %s", err)
+		}
+	}
+	return b.String(), nil
+}
+
+func stepListColumns(item steplist.ItemModel) []string {
+	deprecation := ""
+	if item.IsDeprecated {
+		deprecation = deprecationNotes(item)
+	}
+	return []string{item.ID, item.LatestVersion, item.Title, strings.Join(item.TypeTags, ","), item.Maintainer, deprecation, item.Collection}
+}
+
+func writeStepListTable(w io.Writer, items []steplist.ItemModel, isMultipleCollections bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	// the deprecation is the last column, the color codes of the deprecated rows
+	// would break the alignment of the columns after it
+	header := "ID\tLATEST\tTITLE\tTYPE TAGS\tMAINTAINER\t"
+	if isMultipleCollections {
+		header += "COLLECTION\t"
+	}
+	fmt.Fprintln(tw, header+"DEPRECATED\t")
+	for _, item := range items {
+		columns := stepListColumns(item)
+		collection, deprecation := columns[len(columns)-1], columns[len(columns)-2]
+		columns = columns[:len(columns)-2]
+		if isMultipleCollections {
+			columns = append(columns, collection)
+		}
+		if item.IsDeprecated {
+			deprecation = colorstring.Red(deprecation)
+		}
+		columns = append(columns, deprecation)
+		fmt.Fprintln(tw, strings.Join(columns, "\t")+"\t")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if duplicates := duplicateStepIDs(items); len(duplicates) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, colorstring.Yellow("Steps available in multiple collections:"), strings.Join(duplicates, ", "))
+		fmt.Fprintln(w, "In a bitrise.yml specify the collection of these steps: <collection>::<step-id>@<version>")
 	}
 	return nil
 }
+
+// duplicateStepIDs returns the IDs of the steps which are listed from multiple collections.
+func duplicateStepIDs(items []steplist.ItemModel) []string {
+	collectionsByID := map[string]int{}
+	for _, item := range items {
+		collectionsByID[item.ID]++
+	}
+
+	var duplicates []string
+	for id, count := range collectionsByID {
+		if count > 1 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
+func writeStepListCSV(w io.Writer, items []steplist.ItemModel) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "latest_version", "title", "type_tags", "maintainer", "deprecation", "collection"}); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write(stepListColumns(item)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// deprecationNotes returns the deprecation notes of the step, or "yes" if there's no notes.
+func deprecationNotes(item steplist.ItemModel) string {
+	notes := deprecationSummary(models.StepGroupInfoModel{DeprecateNotes: item.DeprecateNotes, RemovalDate: item.RemovalDate})
+	if notes == "" {
+		return "yes"
+	}
+	return notes
+}
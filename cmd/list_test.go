@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steplist"
+)
+
+func Test_formatStepList(t *testing.T) {
+	items := []steplist.ItemModel{
+		{Collection: "lib", ID: "script", LatestVersion: "1.1.0", Title: "Script", TypeTags: []string{"utility"}, Maintainer: "bitrise"},
+		{Collection: "lib", ID: "old-step", LatestVersion: "1.0.0", Title: "Old, step", TypeTags: []string{"build", "test"}, IsDeprecated: true, DeprecateNotes: "Use script"},
+		{Collection: "lib", ID: "removed-step", LatestVersion: "2.0.0", Title: "Removed", IsDeprecated: true},
+	}
+
+	t.Log("csv")
+	{
+		out, err := formatStepList(items, listFormatCSV, false)
+		require.NoError(t, err)
+		require.Equal(t, `id,latest_version,title,type_tags,maintainer,deprecation,collection
+script,1.1.0,Script,utility,bitrise,,lib
+old-step,1.0.0,"Old, step","build,test",,Use script,lib
+removed-step,2.0.0,Removed,,,yes,lib
+`, out)
+	}
+
+	t.Log("json")
+	{
+		out, err := formatStepList(nil, outputFormatJSON, false)
+		require.NoError(t, err)
+		require.Equal(t, "[]\n", out)
+	}
+
+	t.Log("table without steps")
+	{
+		out, err := formatStepList(nil, "", false)
+		require.NoError(t, err)
+		require.Equal(t, "No steps found\n", out)
+	}
+}
+
+func Test_duplicateStepIDs(t *testing.T) {
+	items := []steplist.ItemModel{
+		{Collection: "lib-a", ID: "script"},
+		{Collection: "lib-b", ID: "script"},
+		{Collection: "lib-a", ID: "git-clone"},
+		{Collection: "lib-b", ID: "deploy"},
+		{Collection: "lib-c", ID: "deploy"},
+	}
+	require.Equal(t, []string{"deploy", "script"}, duplicateStepIDs(items))
+	require.Empty(t, duplicateStepIDs(items[2:4]))
+}
+
+func Test_writeStepListTable(t *testing.T) {
+	items := []steplist.ItemModel{
+		{Collection: "lib-a", ID: "script", LatestVersion: "1.1.0", Title: "Script"},
+		{Collection: "lib-b", ID: "old-step", LatestVersion: "1.0.0", Title: "Old step", IsDeprecated: true, DeprecateNotes: "Use script"},
+	}
+
+	var b strings.Builder
+	require.NoError(t, writeStepListTable(&b, items, true))
+	lines := strings.Split(b.String(), "\n")
+	require.Equal(t, strings.Index(lines[0], "COLLECTION"), strings.Index(lines[1], "lib-a"))
+	require.Equal(t, strings.Index(lines[0], "COLLECTION"), strings.Index(lines[2], "lib-b"))
+	require.Equal(t, strings.Index(lines[0], "DEPRECATED"), strings.Index(lines[2], colorstring.Red("Use script")))
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/bitriseyml"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	outdatedConfigPath = ""
+	outdatedFormat     = ""
+	isOutdatedStrict   = false
+)
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Check the step references of a bitrise.yml",
+	Long: `Check the step references of every workflow of a bitrise.yml against the StepLib.
+
+Every reference is resolved the same way as when the workflow runs, respecting
+the major (git-clone@8) and minor (git-clone@8.1) version locks, and reported if:
+- a newer version of the step is available
+- the step is deprecated
+- the step or the referenced version does not exist
+
+Local, git and StepLib independent steps are not checked.
+The command fails if a step or version does not exist, or with --strict
+if any step is outdated or deprecated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		silenceCheckFailure(cmd)
+		return checkOutdatedSteps(outdatedConfigPath)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().StringVar(&outdatedConfigPath, "config", "bitrise.yml", "bitrise.yml to check")
+	outdatedCmd.Flags().StringVar(&outdatedFormat, "format", "", "Output format. Accepted: raw, json.")
+	outdatedCmd.Flags().BoolVar(&isOutdatedStrict, "strict", false, "Fail on outdated and deprecated steps too")
+}
+
+func checkOutdatedSteps(configPth string) error {
+	switch outdatedFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", outdatedFormat)
+	}
+
+	config, err := bitriseyml.ReadConfig(configPth)
+	if err != nil {
+		return err
+	}
+	references, err := bitriseyml.StepReferences(config)
+	if err != nil {
+		return err
+	}
+
+	collections, err := readReferencedCollections(references)
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]bitriseyml.ReferenceStatusModel, 0, len(references))
+	for _, reference := range references {
+		statuses = append(statuses, bitriseyml.CheckReference(reference, collections[reference.StepLib]))
+	}
+
+	if outdatedFormat == output.FormatJSON {
+		bytes, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize step references, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+	} else {
+		fmt.Println(colorstring.Yellow("Checking step references of:"), configPth)
+		fmt.Println()
+		if len(statuses) == 0 {
+			fmt.Println("No StepLib step references found")
+			return nil
+		}
+		if err := writeReferenceStatusTable(os.Stdout, statuses); err != nil {
+			return fmt.Errorf("failed to write step references table, err: %s", err)
+		}
+	}
+
+	missingCount, outdatedCount, deprecatedCount := countReferenceStatuses(statuses)
+	if outdatedFormat != output.FormatJSON {
+		fmt.Println()
+		fmt.Printf("%d missing, %d outdated, %d deprecated\n", missingCount, outdatedCount, deprecatedCount)
+	}
+	if missingCount > 0 || (isOutdatedStrict && outdatedCount+deprecatedCount > 0) {
+		return checkFailedError{check: "outdated check"}
+	}
+	return nil
+}
+
+// readReferencedCollections reads the StepLibs the step references refer to, by StepLib URI.
+func readReferencedCollections(references []bitriseyml.StepReferenceModel) (map[string]models.StepCollectionModel, error) {
+	collections := map[string]models.StepCollectionModel{}
+	for _, reference := range references {
+		if _, found := collections[reference.StepLib]; found {
+			continue
+		}
+		if err := stepmanutil.EnsureCollectionIsSetUp(reference.StepLib); err != nil {
+			return nil, err
+		}
+		stepLib, err := stepmanutil.ReadStepCollectionModel(reference.StepLib)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read step lib (%s), err: %s", reference.StepLib, err)
+		}
+		collections[reference.StepLib] = stepLib
+	}
+	return collections, nil
+}
+
+func countReferenceStatuses(statuses []bitriseyml.ReferenceStatusModel) (missing, outdated, deprecated int) {
+	for _, status := range statuses {
+		if status.Error != "" {
+			missing++
+		}
+		if status.IsOutdated {
+			outdated++
+		}
+		if status.IsDeprecated {
+			deprecated++
+		}
+	}
+	return
+}
+
+// referenceStatusNotes describes the issues of the step reference, it's empty if the reference is up to date.
+func referenceStatusNotes(status bitriseyml.ReferenceStatusModel) []string {
+	var notes []string
+	if status.Error != "" {
+		notes = append(notes, status.Error)
+	}
+	if status.IsOutdated {
+		notes = append(notes, "newer version available: "+status.LatestVersion)
+	}
+	if status.IsDeprecated {
+		deprecation := deprecationSummary(models.StepGroupInfoModel{DeprecateNotes: status.DeprecateNotes, RemovalDate: status.RemovalDate})
+		if deprecation == "" {
+			notes = append(notes, "deprecated")
+		} else {
+			notes = append(notes, "deprecated: "+deprecation)
+		}
+	}
+	return notes
+}
+
+func writeReferenceStatusTable(w io.Writer, statuses []bitriseyml.ReferenceStatusModel) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WORKFLOW\tSTEP\tRESOLVED\tLATEST\tSTATUS\t")
+	for _, status := range statuses {
+		resolved := status.ResolvedVersion
+		if resolved == "" {
+			resolved = "-"
+		}
+		latest := status.LatestVersion
+		if latest == "" {
+			latest = "-"
+		}
+
+		statusText := colorstring.Green("up to date")
+		if notes := referenceStatusNotes(status); len(notes) > 0 {
+			statusText = strings.Join(notes, ", ")
+			if status.Error != "" {
+				statusText = colorstring.Red(statusText)
+			} else {
+				statusText = colorstring.Yellow(statusText)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n", status.Workflow, status.Composite, resolved, latest, statusText)
+	}
+	return tw.Flush()
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -19,11 +20,33 @@ var RootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
+		var checkFailed checkFailedError
+		if errors.As(err, &checkFailed) {
+			os.Exit(1)
+		}
 		fmt.Println(colorstring.Red("ERROR:"), err)
 		os.Exit(-1)
 	}
 }
 
+// checkFailedError is returned by the check commands (e.g. audit, outdated) if the check found issues.
+// The command has already reported the issues, so the error is not printed, only the exit code is set.
+type checkFailedError struct {
+	check string
+}
+
+// Error ...
+func (err checkFailedError) Error() string {
+	return err.check + " failed"
+}
+
+// silenceCheckFailure disables the error and usage printing of cobra for the command,
+// the check commands report their failures themselves. Invalid args and flags are reported before it's called.
+func silenceCheckFailure(cmd *cobra.Command) {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+}
+
 func init() {
 
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steprun"
+)
+
+var (
+	runStepYMLPath = ""
+	runInputs      = []string{}
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run a step locally",
+	Long: `Run the step of a step.yml locally, without a bitrise.yml.
+
+The inputs get their default value from the step.yml, which can be overridden with --input key=value
+(can be specified multiple times). The inputs are expanded the same way as by the bitrise CLI,
+unless their is_expand option is false.
+
+Bash steps are run with their entry_file, Go steps are built from their package (a Go module) and run.
+The step is run in the current directory.
+
+The outputs exported by the step with envman are collected into a separate env store,
+and printed after the step finished. Sensitive outputs are redacted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStep(runStepYMLPath, runInputs)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVar(&runStepYMLPath, "step-yml", "step.yml", "step.yml of the step")
+	runCmd.Flags().StringArrayVarP(&runInputs, "input", "i", []string{}, "Input of the step, in key=value format")
+}
+
+func runStep(ymlPth string, inputArgs []string) error {
+	inputs, err := steprun.ParseInputs(inputArgs)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("envman"); err != nil {
+		fmt.Println(colorstring.Yellow("envman is not installed, the step can not export outputs"))
+	}
+
+	fmt.Println(colorstring.Yellow("Running step:"), ymlPth)
+	fmt.Println()
+
+	result, err := steprun.Run(steprun.ConfigModel{
+		StepYMLPath: ymlPth,
+		Inputs:      inputs,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run step, err: %s", err)
+	}
+
+	fmt.Println()
+	fmt.Println(colorstring.Yellow("Outputs:"))
+	if len(result.Outputs) == 0 {
+		fmt.Println(" no outputs exported")
+	}
+	for _, output := range result.Outputs {
+		value := output.Value
+		if output.IsSensitive {
+			value = "[REDACTED]"
+		}
+		if output.IsDeclared {
+			fmt.Printf(" %s: %s\n", colorstring.Green(output.Key), value)
+		} else {
+			fmt.Printf(" %s: %s %s\n", colorstring.Yellow(output.Key), value, colorstring.Yellow("(not an output of the step.yml)"))
+		}
+	}
+	fmt.Println()
+
+	if result.ExitCode != 0 {
+		return fmt.Errorf("step failed with exit code: %d", result.ExitCode)
+	}
+	fmt.Println(colorstring.Green("Step succeeded"))
+	return nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/search"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	searchCollection = ""
+	searchFilter     = search.FilterModel{}
+	searchFormat     = ""
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search for steps",
+	Long: `Search for steps in every StepLib collection which is set up,
+or in the given one (--collection).
+
+The id, title, summary, description, type_tags, project_type_tags and input keys
+of the latest version of every step are searched. A step matches the query if every word
+of the query matches any of these, and the results are ranked by relevance:
+matches in the id and title count more than matches in the description.
+
+Deprecated steps are not listed by default, use --include-deprecated to list them too.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.Join(args, " ")
+		if strings.TrimSpace(query) == "" {
+			return errors.New("no search query specified")
+		}
+		return printSearchResults(query)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringVarP(&searchCollection, "collection", "c", "", "Collection (StepLib) to search in - if not specified every collection which is set up is searched"+localCollectionUsage)
+	searchCmd.Flags().StringVar(&searchFilter.TypeTag, "type-tag", "", "Only list steps with the given type tag (e.g. deploy)")
+	searchCmd.Flags().StringVar(&searchFilter.ProjectType, "project-type", "", "Only list steps which can be used for the given project type (e.g. ios)")
+	searchCmd.Flags().BoolVar(&searchFilter.IncludeDeprecated, "include-deprecated", false, "List deprecated steps too")
+	searchCmd.Flags().StringVar(&searchFormat, "format", "", "Output format. Accepted: raw, json.")
+}
+
+func printSearchResults(query string) error {
+	switch searchFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", searchFormat)
+	}
+
+	collectionIDs, err := stepmanutil.CollectionIDs()
+	if err != nil {
+		return fmt.Errorf("failed to read the collections, err: %s", err)
+	}
+	if searchCollection != "" {
+		if err := stepmanutil.EnsureCollectionIsSetUp(searchCollection); err != nil {
+			return err
+		}
+		collectionIDs = []string{searchCollection}
+	}
+	if len(collectionIDs) == 0 {
+		return fmt.Errorf("no collection is set up, you can set up the official Bitrise StepLib with: stepman setup --collection %s", stepmanutil.DefaultCollectionURI)
+	}
+
+	var results []search.ResultModel
+	for _, collectionID := range collectionIDs {
+		index, err := stepmanutil.ReadStepLibIndex(collectionID)
+		if err != nil {
+			return fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+		}
+		results = append(results, search.Collection(collectionID, index.Collection, query, searchFilter)...)
+	}
+	search.Sort(results)
+
+	if searchFormat == output.FormatJSON {
+		if results == nil {
+			results = []search.ResultModel{}
+		}
+		bytes, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize search results, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No steps found for:", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "ID\tVERSION\tSUMMARY\t"
+	if len(collectionIDs) > 1 {
+		header += "COLLECTION\t"
+	}
+	fmt.Fprintln(w, header)
+	for _, result := range results {
+		summary := firstLine(result.Summary)
+		if result.IsDeprecated {
+			summary = "[deprecated] " + summary
+		}
+		line := result.ID + "\t" + result.Version + "\t" + summary + "\t"
+		if len(collectionIDs) > 1 {
+			line += result.Collection + "\t"
+		}
+		fmt.Fprintln(w, line)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Println(colorstring.Red("Failed to print search results:"), err)
+	}
+	return nil
+}
+
+func firstLine(text string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+}
@@ -2,37 +2,216 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/bitrise-io/bitrise/tools"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/share"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	shareStepYMLPath     = ""
+	shareBitriseYMLPath  = ""
+	shareParamsFromFlags = share.ParamsModel{}
+	isShareDryRun        = false
 )
 
 // shareCmd represents the share command
 var shareCmd = &cobra.Command{
 	Use:   "share",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Work your own magic here
-		fmt.Println("share called")
+	Short: "Share the step (version) into a StepLib",
+	Long: `Share the step (version) into a StepLib.
+
+Audits the step.yml, then starts the sharing with the StepLib fork,
+integrates the step version into it and finishes the sharing.
+Once it's done, the only thing left is to create a Pull Request from the StepLib fork.
+
+The share params are read from the app envs of the step's bitrise.yml:
+  BITRISE_STEP_ID, BITRISE_STEP_VERSION, BITRISE_STEP_GIT_CLONE_URL and MY_STEPLIB_REPO_FORK_GIT_URL
+Flags take precedence over the values of the bitrise.yml.
+
+Each step of the flow can be called separately too, with the subcommands.
+Use --dry-run to print the StepLib entry which would be created, without touching the StepLib fork.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := readShareParams(!isShareDryRun)
+		if err != nil {
+			return err
+		}
+
+		if err := auditStepForShare(); err != nil {
+			return err
+		}
+
+		if isShareDryRun {
+			return printStepLibEntry(params)
+		}
+
+		if err := startShare(params); err != nil {
+			return err
+		}
+		if err := createShare(params); err != nil {
+			return err
+		}
+		return finishShare()
+	},
+}
+
+var shareAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit the step.yml before sharing",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return auditStepForShare()
+	},
+}
+
+var shareStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start sharing with the StepLib fork",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := readShareParams(true)
+		if err != nil {
+			return err
+		}
+		if isShareDryRun {
+			fmt.Println("Would start sharing with the StepLib fork:", colorstring.Yellow(params.StepLibForkURL))
+			return nil
+		}
+		return startShare(params)
+	},
+}
+
+var shareCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Integrate the step version into the StepLib fork",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := readShareParams(false)
+		if err != nil {
+			return err
+		}
+		if isShareDryRun {
+			return printStepLibEntry(params)
+		}
+		return createShare(params)
+	},
+}
+
+var shareFinishCmd = &cobra.Command{
+	Use:   "finish",
+	Short: "Finish sharing: push the step version to the StepLib fork",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isShareDryRun {
+			fmt.Println("Would push the shared step version to the StepLib fork")
+			return nil
+		}
+		return finishShare()
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(shareCmd)
+	shareCmd.AddCommand(shareAuditCmd)
+	shareCmd.AddCommand(shareStartCmd)
+	shareCmd.AddCommand(shareCreateCmd)
+	shareCmd.AddCommand(shareFinishCmd)
+
+	shareCmd.PersistentFlags().StringVar(&shareStepYMLPath, "step-yml", "step.yml", "step.yml of the step to share")
+	shareCmd.PersistentFlags().StringVar(&shareBitriseYMLPath, "config", "bitrise.yml", "bitrise.yml of the step, to read the share params from")
+	shareCmd.PersistentFlags().StringVar(&shareParamsFromFlags.StepID, "stepid", "", "ID of the step (overrides BITRISE_STEP_ID)")
+	shareCmd.PersistentFlags().StringVar(&shareParamsFromFlags.Version, "tag", "", "Version (git tag) of the step to share (overrides BITRISE_STEP_VERSION)")
+	shareCmd.PersistentFlags().StringVar(&shareParamsFromFlags.GitCloneURL, "git", "", "Git clone URL of the step (overrides BITRISE_STEP_GIT_CLONE_URL)")
+	shareCmd.PersistentFlags().StringVarP(&shareParamsFromFlags.StepLibForkURL, "collection", "c", "", "Git URL of your StepLib fork (overrides MY_STEPLIB_REPO_FORK_GIT_URL)")
+	shareCmd.PersistentFlags().BoolVar(&isShareDryRun, "dry-run", false, "Print what would be shared, without touching the StepLib fork")
+}
+
+func readShareParams(isForkRequired bool) (share.ParamsModel, error) {
+	params := share.ParamsModel{}
+	if exist, err := pathutil.IsPathExists(shareBitriseYMLPath); err != nil {
+		return share.ParamsModel{}, fmt.Errorf("failed to check if bitrise.yml (%s) exists: %s", shareBitriseYMLPath, err)
+	} else if exist {
+		params, err = share.ReadParamsFromBitriseYML(shareBitriseYMLPath)
+		if err != nil {
+			return share.ParamsModel{}, err
+		}
+	}
+
+	params = params.Merge(shareParamsFromFlags)
+	if err := params.Validate(isForkRequired); err != nil {
+		return share.ParamsModel{}, fmt.Errorf("%s - define them in the bitrise.yml (%s) or with flags", err, shareBitriseYMLPath)
+	}
+	return params, nil
+}
+
+func auditStepForShare() error {
+	return auditStepYML(shareStepYMLPath, false)
+}
+
+func startShare(params share.ParamsModel) error {
+	fmt.Println(colorstring.Yellow("Starting share with StepLib fork:"), params.StepLibForkURL)
+	if err := tools.StepmanShareStart(params.StepLibForkURL); err != nil {
+		return fmt.Errorf("failed to start sharing: %s", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+func createShare(params share.ParamsModel) error {
+	fmt.Println(colorstring.Yellow("Integrating step version into the StepLib fork:"), params.StepID+"@"+params.Version)
+	if err := tools.StepmanShareCreate(params.Version, params.GitCloneURL, params.StepID); err != nil {
+		return fmt.Errorf("failed to integrate step version: %s", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+func finishShare() error {
+	fmt.Println(colorstring.Yellow("Finishing share"))
+	if err := tools.StepmanShareFinish(); err != nil {
+		return fmt.Errorf("failed to finish sharing: %s", err)
+	}
+	return nil
+}
 
-	// Here you will define your flags and configuration settings.
+func printStepLibEntry(params share.ParamsModel) error {
+	commit, err := share.ReadTagCommit(filepath.Dir(shareStepYMLPath), params.Version)
+	if err != nil {
+		fmt.Println(colorstring.Yellow("[!] The commit of the version tag can't be determined locally:"), err)
+		fmt.Println("    Don't forget to create and push the git tag before sharing!")
+		fmt.Println()
+		commit = fmt.Sprintf("<commit of tag %s>", params.Version)
+	}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// shareCmd.PersistentFlags().String("foo", "", "A help for foo")
+	isNewStep := true
+	if params.StepLibForkURL != "" {
+		stepLib, found, err := stepmanutil.ReadStepLib(params.StepLibForkURL)
+		if err != nil {
+			return fmt.Errorf("failed to read the StepLib fork: %s", err)
+		}
+		if found {
+			exist, err := pathutil.IsPathExists(filepath.Dir(stepLib.StepGroupInfoPath(params.StepID)))
+			if err != nil {
+				return fmt.Errorf("failed to check if step exists in the StepLib fork: %s", err)
+			}
+			isNewStep = !exist
+		}
+	}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// shareCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	entry, err := share.GenerateStepLibEntry(shareStepYMLPath, params, commit, isNewStep)
+	if err != nil {
+		return err
+	}
 
+	fmt.Println(colorstring.Yellow("Dry run, the StepLib fork is not touched."))
+	fmt.Println("The following StepLib entry would be created:")
+	fmt.Println()
+	fmt.Println(colorstring.Green(entry.Path) + ":")
+	fmt.Println(entry.Content)
+	if entry.StepInfoPath != "" {
+		fmt.Println(colorstring.Green(entry.StepInfoPath) + ":")
+		fmt.Println(entry.StepInfoContent)
+	}
+	return nil
 }
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/docs"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	snippetCollection  = ""
+	snippetStepYMLPath = ""
+)
+
+// snippetCmd represents the snippet command
+var snippetCmd = &cobra.Command{
+	Use:   "snippet <id>[@version]",
+	Short: "Print a bitrise.yml snippet of a step",
+	Long: `Print a ready to paste bitrise.yml step block of a step, with every input of the step.
+
+Required inputs are set to their default value, and sensitive inputs to a secret env var
+with the name of the input (e.g. $API_KEY). The other inputs are commented out with their
+default value. Every input has a comment with its title, whether it's required or sensitive,
+and its value options.
+
+The step is read from the StepLib, the version can be a version constraint (e.g. git-clone@8).
+If the version is not specified, the major version of the latest version is referenced.
+A local step can be referenced with --step-yml, the snippet references it with a path:: reference.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if snippetStepYMLPath != "" {
+			if len(args) > 0 {
+				return errors.New("step ID and --step-yml can not be specified at the same time")
+			}
+			return printLocalStepSnippet(snippetStepYMLPath)
+		}
+
+		if len(args) < 1 {
+			return errors.New("no step ID specified as a parameter")
+		}
+		stepID, version, _ := strings.Cut(args[0], "@")
+		return printLibraryStepSnippet(stepmanutil.CollectionOrDefault(snippetCollection), stepID, version)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(snippetCmd)
+	snippetCmd.Flags().StringVarP(&snippetCollection, "collection", "c", "", "Collection (StepLib) of the step - if not specified the "+stepmanutil.CollectionEnvKey+" env or the official Bitrise StepLib is used"+localCollectionUsage)
+	snippetCmd.Flags().StringVar(&snippetStepYMLPath, "step-yml", "", "step.yml of a local step")
+}
+
+func printLibraryStepSnippet(collectionID, stepID, version string) error {
+	stepInfo, resolution, err := readStepInfoFromLibrary(collectionID, stepID, version)
+	if err != nil {
+		return err
+	}
+
+	if version == "" {
+		version = strings.Split(resolution.Version, ".")[0]
+	}
+	reference := stepID + "@" + version
+	if collectionID != stepmanutil.DefaultCollectionURI {
+		reference = stepmanutil.CollectionURI(collectionID) + "::" + reference
+	}
+
+	snippet, err := docs.Snippet(reference, stepInfo.Step)
+	if err != nil {
+		return fmt.Errorf("failed to generate snippet, err: %s", err)
+	}
+	fmt.Print(snippet)
+	return nil
+}
+
+func printLocalStepSnippet(ymlPth string) error {
+	step, err := stepman.ParseStepDefinition(ymlPth, false)
+	if err != nil {
+		return fmt.Errorf("failed to parse step.yml (path: %s), error: %s", ymlPth, err)
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(ymlPth))
+	if !filepath.IsAbs(dir) && !strings.HasPrefix(dir, ".") {
+		dir = "./" + dir
+	}
+
+	snippet, err := docs.Snippet("path::"+dir, step)
+	if err != nil {
+		return fmt.Errorf("failed to generate snippet, err: %s", err)
+	}
+	fmt.Print(snippet)
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// stepLibStaleAge is the age of the StepLib cache, after which an update is suggested
+const stepLibStaleAge = 7 * 24 * time.Hour
+
+var (
+	stepLibCollection = ""
+	stepLibFormat     = ""
+	isStepLibUpdate   = false
+)
+
+// stepLibCmd represents the steplib command
+var stepLibCmd = &cobra.Command{
+	Use:   "steplib",
+	Short: "Show the locally cached StepLibs",
+	Long: `Show the StepLibs (collections) which are set up, with the time
+their local cache was last updated.
+
+Every other command reads the StepLibs from the local cache, without network access.
+Use --update to update the cache first (requires network access).
+
+The list, search, deprecated and info commands read a slim index of the StepLibs, which is
+cached in the user cache directory (or in the directory set in the ` + stepmanutil.IndexCacheDirEnvKey + ` env),
+and rebuilt when the StepLib is updated.
+
+The StepLibs are read from ~/.stepman, or from the directory set in the ` + stepmanutil.StepmanHomeEnvKey + ` env.
+Updating is only supported in ~/.stepman.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printStepLibs(stepLibCollection, isStepLibUpdate)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(stepLibCmd)
+	stepLibCmd.Flags().StringVarP(&stepLibCollection, "collection", "c", "", "Collection (StepLib) - if not specified every collection which is set up is shown")
+	stepLibCmd.Flags().StringVar(&stepLibFormat, "format", "", "Output format. Accepted: raw, json.")
+	stepLibCmd.Flags().BoolVar(&isStepLibUpdate, "update", false, "Update the collections before showing them")
+}
+
+// stepLibStatusModel describes the local cache of a StepLib
+type stepLibStatusModel struct {
+	URI         string     `json:"uri"`
+	SpecPath    string     `json:"spec_path,omitempty"`
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+	// AgeSeconds is the time elapsed since the spec was generated
+	AgeSeconds *int64 `json:"age_seconds,omitempty"`
+	StepCount  int    `json:"step_count"`
+}
+
+func newStepLibStatus(stepLib stepmanutil.StepLibModel, spec models.StepCollectionModel, now time.Time) stepLibStatusModel {
+	status := stepLibStatusModel{
+		URI:       stepLib.URI,
+		SpecPath:  stepLib.SpecPath(),
+		StepCount: len(spec.Steps),
+	}
+	if generatedAt, found := stepmanutil.SpecGeneratedAt(spec); found {
+		age := int64(now.Sub(generatedAt).Seconds())
+		status.GeneratedAt = &generatedAt
+		status.AgeSeconds = &age
+	}
+	return status
+}
+
+// formatAge returns the age in a human readable format, e.g. 3 days or 5 hours.
+func formatAge(age time.Duration) string {
+	plural := func(count int, unit string) string {
+		if count == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", count, unit)
+	}
+
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age/time.Minute), "minute")
+	case age < 24*time.Hour:
+		return plural(int(age/time.Hour), "hour")
+	}
+	return plural(int(age/(24*time.Hour)), "day")
+}
+
+func printStepLibs(collectionID string, isUpdate bool) error {
+	switch stepLibFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", stepLibFormat)
+	}
+
+	collectionIDs, err := stepmanutil.CollectionIDs()
+	if err != nil {
+		return fmt.Errorf("failed to read the collections, err: %s", err)
+	}
+	if collectionID != "" {
+		if err := stepmanutil.EnsureCollectionIsSetUp(collectionID); err != nil {
+			return err
+		}
+		collectionIDs = []string{collectionID}
+	}
+	if len(collectionIDs) == 0 {
+		return fmt.Errorf("no collection is set up in %s, you can set up the official Bitrise StepLib with: stepman setup --collection %s", stepmanutil.StepmanHomeDir(), stepmanutil.DefaultCollectionURI)
+	}
+
+	now := time.Now()
+	statuses := make([]stepLibStatusModel, 0, len(collectionIDs))
+	for _, id := range collectionIDs {
+		if isUpdate {
+			if stepLibFormat != output.FormatJSON {
+				fmt.Println(colorstring.Yellow("Updating collection:"), id)
+			}
+			if _, err := stepmanutil.UpdateCollection(id); err != nil {
+				return err
+			}
+		}
+
+		stepLib, found, err := stepmanutil.ReadStepLib(id)
+		if err != nil {
+			return fmt.Errorf("failed to read step lib (%s), err: %s", id, err)
+		} else if !found {
+			return fmt.Errorf("no route found for collection: %s", id)
+		}
+		spec, err := stepLib.ReadSpec()
+		if err != nil {
+			return fmt.Errorf("failed to read step lib (%s), err: %s", id, err)
+		}
+		statuses = append(statuses, newStepLibStatus(stepLib, spec, now))
+	}
+
+	if stepLibFormat == output.FormatJSON {
+		bytes, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize collections, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	if isUpdate {
+		fmt.Println()
+	}
+	fmt.Println(colorstring.Yellow("Collections in:"), stepmanutil.StepmanHomeDir())
+	fmt.Println()
+
+	isStale := false
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tSTEPS\tUPDATED AT\tAGE\t")
+	for _, status := range statuses {
+		updatedAt, age := "-", "unknown"
+		if status.GeneratedAt != nil {
+			ageDuration := time.Duration(*status.AgeSeconds) * time.Second
+			updatedAt = status.GeneratedAt.Format("2006-01-02 15:04")
+			age = formatAge(ageDuration)
+			isStale = isStale || ageDuration > stepLibStaleAge
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t\n", status.URI, status.StepCount, updatedAt, age)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if isStale && !isUpdate {
+		fmt.Println()
+		fmt.Println(colorstring.Yellow("Some collections were not updated for more than a week, update them with: --update"))
+	}
+	return nil
+}
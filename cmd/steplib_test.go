@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAge(t *testing.T) {
+	require.Equal(t, "just now", formatAge(30*time.Second))
+	require.Equal(t, "1 minute", formatAge(time.Minute))
+	require.Equal(t, "45 minutes", formatAge(45*time.Minute))
+	require.Equal(t, "5 hours", formatAge(5*time.Hour+10*time.Minute))
+	require.Equal(t, "1 day", formatAge(30*time.Hour))
+	require.Equal(t, "12 days", formatAge(12*24*time.Hour))
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steptest"
+)
+
+var (
+	testConfigPath   = ""
+	testJUnitPath    = ""
+	testJSONPath     = ""
+	testCaseNames    = []string{}
+	isTestVerboseLog = false
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run the test cases of a step",
+	Long: `Run the test cases of a step, defined in a step_test.yml:
+
+  step_yml: step.yml        # the tested step.yml, relative to the step_test.yml
+  cases:
+  - name: greets the user
+    inputs:                 # overrides of the input defaults
+      name: World
+    envs:                   # additional envs, the inputs can reference them
+      GREETER_NAME: Bitrise
+    exit_code: 0            # the expected exit code
+    outputs:                # the expected values of the exported outputs
+      GREETING: Hello World
+    stdout:                 # regular expressions, each has to match the standard output
+    - "Hello"
+
+Every case runs the step the same way as the run command does, in the current directory,
+with a separate env store for the exported outputs.
+
+The results can be written into a JUnit XML (--junit) and a JSON (--json) report too.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		silenceCheckFailure(cmd)
+		return runStepTests(testConfigPath)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(testCmd)
+	testCmd.Flags().StringVar(&testConfigPath, "config", steptest.DefaultConfigPath, "Test cases of the step")
+	testCmd.Flags().StringVar(&testJUnitPath, "junit", "", "Path of the JUnit XML report")
+	testCmd.Flags().StringVar(&testJSONPath, "json", "", "Path of the JSON report")
+	testCmd.Flags().StringArrayVar(&testCaseNames, "case", []string{}, "Name of the test case to run, can be specified multiple times - if not specified every case is run")
+	testCmd.Flags().BoolVar(&isTestVerboseLog, "verbose", false, "Print the output of the step")
+}
+
+func runStepTests(configPth string) error {
+	config, err := steptest.ReadConfig(configPth)
+	if err != nil {
+		return err
+	}
+
+	cases := config.Cases
+	if len(testCaseNames) > 0 {
+		cases = nil
+		for _, testCase := range config.Cases {
+			if slices.Contains(testCaseNames, testCase.Name) {
+				cases = append(cases, testCase)
+			}
+		}
+		for _, name := range testCaseNames {
+			if !slices.ContainsFunc(cases, func(testCase steptest.CaseModel) bool { return testCase.Name == name }) {
+				return fmt.Errorf("no test case found with name: %s", name)
+			}
+		}
+	}
+
+	stepYMLPth := config.StepYMLPath(configPth)
+	fmt.Println(colorstring.Yellow("Testing step:"), stepYMLPth)
+	fmt.Println()
+
+	var results []steptest.CaseResultModel
+	for _, testCase := range cases {
+		var stepLog io.Writer
+		if isTestVerboseLog {
+			fmt.Println(colorstring.Yellow("Running test case:"), testCase.Name)
+			stepLog = os.Stdout
+		}
+		result := steptest.RunCase(stepYMLPth, testCase, stepLog)
+		if isTestVerboseLog {
+			fmt.Println()
+		}
+		results = append(results, result)
+
+		switch {
+		case result.Error != "":
+			fmt.Println(" *", colorstring.Red("[error]"), result.Name)
+			fmt.Println("   ", result.Error)
+		case len(result.Failures) > 0:
+			fmt.Println(" *", colorstring.Red("[failed]"), result.Name)
+			for _, failure := range result.Failures {
+				fmt.Println("   ", failure)
+			}
+		default:
+			fmt.Println(" *", colorstring.Green("[OK]"), result.Name, fmt.Sprintf("(%.1fs)", result.Duration.Seconds()))
+		}
+	}
+
+	report := steptest.NewReport(configPth, results)
+	if testJUnitPath != "" {
+		if err := report.WriteJUnit(testJUnitPath); err != nil {
+			return err
+		}
+	}
+	if testJSONPath != "" {
+		if err := report.WriteJSON(testJSONPath); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%d passed, %d failed, %d error(s)\n", report.Tests-report.Failures-report.Errors, report.Failures, report.Errors)
+	fmt.Println()
+	if report.Failures > 0 || report.Errors > 0 {
+		return checkFailedError{check: "step test"}
+	}
+	return nil
+}
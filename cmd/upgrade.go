@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/bitriseyml"
+	"github.com/bitrise-io/bitrise-plugins-step/stepdiff"
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	upgradeConfigPath = ""
+	upgradePolicy     = ""
+	isUpgradeDryRun   = false
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the step references of a bitrise.yml",
+	Long: `Upgrade the step references of every workflow of a bitrise.yml
+to the latest version of the step within the upgrade policy:
+- patch: the latest patch version of the same minor version (git-clone@8.1.0 -> git-clone@8.1.2)
+- minor: the latest minor version of the same major version (git-clone@8.1.0 -> git-clone@8.2.0)
+- major: the latest version (git-clone@7.0.0 -> git-clone@8.2.0)
+- lock: the latest version, keeping the major or minor lock of the reference (git-clone@7 -> git-clone@8)
+
+The patch, minor and major policies pin the upgraded references to the full version.
+References without version are always the latest, these are not changed.
+
+Only the step references are rewritten, comments and formatting of the bitrise.yml are kept.
+The breaking changes (e.g. removed or new required inputs) of each upgraded step are listed.
+Use --dry-run to print the changes of the bitrise.yml instead of writing it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return upgradeStepReferences(upgradeConfigPath, upgradePolicy, isUpgradeDryRun)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().StringVar(&upgradeConfigPath, "config", "bitrise.yml", "bitrise.yml to upgrade")
+	upgradeCmd.Flags().StringVar(&upgradePolicy, "policy", bitriseyml.PolicyMinor, "Upgrade policy: "+strings.Join(bitriseyml.Policies, ", "))
+	upgradeCmd.Flags().BoolVar(&isUpgradeDryRun, "dry-run", false, "Print the changes instead of writing the bitrise.yml")
+}
+
+func upgradeStepReferences(configPth, policy string, isDryRun bool) error {
+	if !slices.Contains(bitriseyml.Policies, policy) {
+		return fmt.Errorf("invalid policy: %s, available: %s", policy, strings.Join(bitriseyml.Policies, ", "))
+	}
+
+	config, err := bitriseyml.ReadConfig(configPth)
+	if err != nil {
+		return err
+	}
+	references, err := bitriseyml.StepReferences(config)
+	if err != nil {
+		return err
+	}
+	collections, err := readReferencedCollections(references)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(colorstring.Yellow("Upgrading step references of:"), configPth, "(policy: "+policy+")")
+	fmt.Println()
+
+	var upgrades []bitriseyml.UpgradeModel
+	replacements := map[string]string{}
+	planned := map[string]bool{}
+	for _, reference := range references {
+		if planned[reference.Composite] {
+			continue
+		}
+		planned[reference.Composite] = true
+
+		upgrade, found, err := bitriseyml.PlanUpgrade(reference, collections[reference.StepLib], policy)
+		if err != nil {
+			fmt.Println(" *", colorstring.Yellow("[skipped]"), reference.Composite+":", err)
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		upgrades = append(upgrades, upgrade)
+		replacements[upgrade.Composite] = upgrade.NewComposite
+	}
+
+	if len(upgrades) == 0 {
+		fmt.Println(" *", colorstring.Green("[OK]"), "every step reference is up to date within the policy")
+		return nil
+	}
+
+	for _, upgrade := range upgrades {
+		if err := printUpgradeSummary(upgrade, collections[upgrade.StepLib]); err != nil {
+			return err
+		}
+	}
+	fmt.Println()
+
+	content, err := os.ReadFile(configPth)
+	if err != nil {
+		return fmt.Errorf("failed to read bitrise.yml (%s), err: %s", configPth, err)
+	}
+	upgraded, count, err := bitriseyml.RewriteStepReferences(string(content), replacements)
+	if err != nil {
+		return err
+	}
+
+	if isDryRun {
+		fmt.Print(lineDiff(configPth, string(content), upgraded))
+		return nil
+	}
+
+	info, err := os.Stat(configPth)
+	if err != nil {
+		return fmt.Errorf("failed to read bitrise.yml (%s) permissions, err: %s", configPth, err)
+	}
+	if err := os.WriteFile(configPth, []byte(upgraded), info.Mode()); err != nil {
+		return fmt.Errorf("failed to write bitrise.yml (%s), err: %s", configPth, err)
+	}
+	fmt.Printf("%d step reference(s) upgraded in %s\n", count, configPth)
+	return nil
+}
+
+// printUpgradeSummary prints the upgrade and the breaking changes between the old and the new version.
+func printUpgradeSummary(upgrade bitriseyml.UpgradeModel, collection models.StepCollectionModel) error {
+	fmt.Printf(" * %s -> %s (%s -> %s)\n", upgrade.Composite, colorstring.Green(upgrade.NewComposite), upgrade.FromVersion, upgrade.ToVersion)
+
+	from, _, err := stepmanutil.StepVersion(collection, upgrade.ID, upgrade.FromVersion)
+	if err != nil {
+		return err
+	}
+	to, _, err := stepmanutil.StepVersion(collection, upgrade.ID, upgrade.ToVersion)
+	if err != nil {
+		return err
+	}
+	changes, err := stepdiff.Steps(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to compare step (%s) versions, err: %s", upgrade.ID, err)
+	}
+
+	if !stepdiff.HasBreakingChange(changes) {
+		fmt.Println("   - no breaking changes")
+		return nil
+	}
+	for _, change := range changes {
+		if change.IsBreaking {
+			fmt.Println("   -", colorstring.Red("[breaking]"), change)
+		}
+	}
+	return nil
+}
+
+// lineDiff returns the changed lines of the content in unified diff format,
+// the old and the new content are expected to have the same number of lines.
+func lineDiff(pth, oldContent, newContent string) string {
+	oldLines := strings.Split(oldContent, "\n")
+	newLines := strings.Split(newContent, "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n", pth)
+	fmt.Fprintf(&b, "+++ %s\n", pth)
+	for i := 0; i < len(oldLines) && i < len(newLines); i++ {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+		fmt.Fprintf(&b, "@@ -%d +%d @@\n", i+1, i+1)
+		fmt.Fprintf(&b, "-%s\n", oldLines[i])
+		fmt.Fprintf(&b, "+%s\n", newLines[i])
+	}
+	return b.String()
+}
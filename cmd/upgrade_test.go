@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineDiff(t *testing.T) {
+	oldContent := "workflows:\n  primary:\n    steps:\n    - git-clone@8.1.0: {}\n    - script@1.0.0: {}\n"
+	newContent := "workflows:\n  primary:\n    steps:\n    - git-clone@8.2.0: {}\n    - script@1.0.0: {}\n"
+
+	require.Equal(t, `--- bitrise.yml
++++ bitrise.yml
+@@ -4 +4 @@
+-    - git-clone@8.1.0: {}
++    - git-clone@8.2.0: {}
+`, lineDiff("bitrise.yml", oldContent, newContent))
+}
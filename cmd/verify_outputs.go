@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
+	"github.com/bitrise-io/bitrise-plugins-step/steprun"
+)
+
+var (
+	verifyOutputsStepYMLPath = ""
+	verifyOutputsInputs      = []string{}
+	isVerifyOutputsStrict    = false
+)
+
+// verifyOutputsCmd represents the verify-outputs command
+var verifyOutputsCmd = &cobra.Command{
+	Use:   "verify-outputs",
+	Short: "Verify that a step exports the outputs of its step.yml",
+	Long: `Run the step of a step.yml locally (the same way as the run command does),
+and compare the envs it exported with envman to the outputs of the step.yml:
+- declared outputs which were not exported (error)
+- exported envs which are not declared as outputs (warning)
+- outputs exported with an empty value (warning)
+
+The inputs of the run can be set with --input key=value.
+The command fails if there's any error, or with --strict if there's any warning.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		silenceCheckFailure(cmd)
+		return verifyStepOutputs(verifyOutputsStepYMLPath, verifyOutputsInputs, isVerifyOutputsStrict)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyOutputsCmd)
+	verifyOutputsCmd.Flags().StringVar(&verifyOutputsStepYMLPath, "step-yml", "step.yml", "step.yml of the step")
+	verifyOutputsCmd.Flags().StringArrayVarP(&verifyOutputsInputs, "input", "i", []string{}, "Input of the step, in key=value format")
+	verifyOutputsCmd.Flags().BoolVar(&isVerifyOutputsStrict, "strict", false, "Fail on warnings too")
+}
+
+func verifyStepOutputs(ymlPth string, inputArgs []string, isStrict bool) error {
+	inputs, err := steprun.ParseInputs(inputArgs)
+	if err != nil {
+		return err
+	}
+	step, err := stepman.ParseStepDefinition(ymlPth, false)
+	if err != nil {
+		return fmt.Errorf("failed to parse step.yml (path: %s), error: %s", ymlPth, err)
+	}
+
+	fmt.Println(colorstring.Yellow("Running step:"), ymlPth)
+	fmt.Println()
+
+	result, err := steprun.Run(steprun.ConfigModel{
+		StepYMLPath: ymlPth,
+		Inputs:      inputs,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run step, err: %s", err)
+	}
+
+	findings, err := audit.Outputs(step, result.Outputs)
+	if err != nil {
+		return fmt.Errorf("failed to verify outputs, err: %s", err)
+	}
+	if result.ExitCode != 0 {
+		findings = append([]audit.FindingModel{{
+			Severity: audit.SeverityError,
+			Subject:  "step",
+			Message:  fmt.Sprintf("failed with exit code %d", result.ExitCode),
+		}}, findings...)
+	}
+
+	fmt.Println()
+	fmt.Println(colorstring.Yellow("Verifying outputs:"), ymlPth)
+	for _, finding := range findings {
+		switch finding.Severity {
+		case audit.SeverityError:
+			fmt.Println(" *", colorstring.Red("[error]"), finding)
+		default:
+			fmt.Println(" *", colorstring.Yellow("[warning]"), finding)
+		}
+	}
+
+	errorCount, warningCount := audit.Count(findings)
+	if errorCount == 0 && warningCount == 0 {
+		fmt.Println(" *", colorstring.Green("[OK]"), "every output is exported")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%d error(s), %d warning(s)\n", errorCount, warningCount)
+	fmt.Println()
+	if errorCount > 0 || (isStrict && warningCount > 0) {
+		return checkFailedError{check: "output verification"}
+	}
+	return nil
+}
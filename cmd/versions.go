@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bitrise-io/bitrise/output"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/spf13/cobra"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+var (
+	versionsCollection = ""
+	versionsSince      = ""
+	versionsFormat     = ""
+)
+
+// versionsCmd represents the versions command
+var versionsCmd = &cobra.Command{
+	Use:   "versions <step-id>",
+	Short: "List the versions of a step",
+	Long: `List every version of a step in the StepLib, highest first,
+with the date it was published at and the source commit it was shared from.
+
+Use --since to only list the versions released after a given version (e.g. 8.1.0)
+or published at or after a given date (e.g. 2024-01-31).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("no step ID specified as a parameter")
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("more than one step ID specified: %s", args)
+		}
+
+		return printStepVersions(stepmanutil.CollectionOrDefault(versionsCollection), args[0])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(versionsCmd)
+	versionsCmd.Flags().StringVarP(&versionsCollection, "collection", "c", "", "Collection (StepLib) of the step - if not specified the "+stepmanutil.CollectionEnvKey+" env or the official Bitrise StepLib is used"+localCollectionUsage)
+	versionsCmd.Flags().StringVar(&versionsSince, "since", "", "Only list the versions released after the given version, or published since the given date")
+	versionsCmd.Flags().StringVar(&versionsFormat, "format", "", "Output format. Accepted: raw, json.")
+}
+
+// stepVersionsModel is the json output of the versions command
+type stepVersionsModel struct {
+	Library        string                                `json:"library"`
+	ID             string                                `json:"id"`
+	LatestVersion  string                                `json:"latest_version"`
+	DeprecateNotes string                                `json:"deprecate_notes,omitempty"`
+	RemovalDate    string                                `json:"removal_date,omitempty"`
+	Versions       []stepmanutil.VersionHistoryItemModel `json:"versions"`
+}
+
+func printStepVersions(collectionID, stepID string) error {
+	switch versionsFormat {
+	case "", output.FormatRaw, output.FormatJSON:
+	default:
+		return fmt.Errorf("invalid format: %s", versionsFormat)
+	}
+
+	if err := stepmanutil.EnsureCollectionIsSetUp(collectionID); err != nil {
+		return err
+	}
+
+	collection, err := stepmanutil.ReadStepCollectionModel(collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to read step lib (%s), error: %s", collectionID, err)
+	}
+
+	history, err := stepmanutil.StepVersionHistory(collection, stepID)
+	if err != nil {
+		return err
+	}
+	if versionsSince != "" {
+		history, err = stepmanutil.FilterVersionHistorySince(history, versionsSince)
+		if err != nil {
+			return err
+		}
+	}
+
+	stepGroup := collection.Steps[stepID]
+	stepVersions := stepVersionsModel{
+		Library:        collectionID,
+		ID:             stepID,
+		LatestVersion:  stepGroup.LatestVersionNumber,
+		DeprecateNotes: stepGroup.Info.DeprecateNotes,
+		RemovalDate:    stepGroup.Info.RemovalDate,
+		Versions:       history,
+	}
+	if stepVersions.Versions == nil {
+		stepVersions.Versions = []stepmanutil.VersionHistoryItemModel{}
+	}
+
+	if versionsFormat == output.FormatJSON {
+		bytes, err := json.MarshalIndent(stepVersions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize step versions, err: %s", err)
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+
+	printStepVersionsTable(stepVersions, stepGroup.Info)
+	return nil
+}
+
+func printStepVersionsTable(stepVersions stepVersionsModel, info models.StepGroupInfoModel) {
+	fmt.Println(colorstring.Green(stepVersions.ID) + "  [" + stepVersions.Library + "]")
+	if isDeprecated(info) {
+		fmt.Println(colorstring.Red("Deprecated") + ": " + deprecationSummary(info))
+	}
+	fmt.Println()
+
+	if len(stepVersions.Versions) == 0 {
+		fmt.Println("No versions found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tPUBLISHED AT\tCOMMIT\t")
+	for _, item := range stepVersions.Versions {
+		publishedAt := "-"
+		if item.PublishedAt != nil {
+			publishedAt = item.PublishedAt.Format("2006-01-02")
+		}
+		commit := "-"
+		if item.SourceCommit != "" {
+			commit = shortCommit(item.SourceCommit)
+		}
+
+		var markers []string
+		if item.IsLatest {
+			markers = append(markers, "latest")
+		}
+		if item.IsDeprecated {
+			markers = append(markers, "deprecated")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Version, publishedAt, commit, strings.Join(markers, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Println(colorstring.Red("Failed to print versions:"), err)
+	}
+}
+
+// deprecationSummary returns the deprecation notes and the removal date of the step, in one line.
+func deprecationSummary(info models.StepGroupInfoModel) string {
+	summary := info.DeprecateNotes
+	if info.RemovalDate != "" {
+		if summary != "" {
+			summary += " "
+		}
+		summary += "(removal date: " + info.RemovalDate + ")"
+	}
+	return summary
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/bitrise-io/bitrise-plugins-step/audit"
 	"github.com/bitrise-io/go-utils/colorstring"
 	"github.com/bitrise-io/go-utils/command"
 	"github.com/bitrise-io/go-utils/fileutil"
@@ -17,13 +19,18 @@ import (
 	"github.com/bitrise-io/goinp/goinp"
 	"github.com/bitrise-io/gows/goutil"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
 const (
-	toolkitTypeBash = "bash"
-	toolkitTypeGo   = "go"
+	toolkitTypeBash   = "bash"
+	toolkitTypeGo     = "go"
+	toolkitTypeSwift  = "swift"
+	toolkitTypeKotlin = "kotlin"
 )
 
+var toolkitTypes = []string{toolkitTypeBash, toolkitTypeGo, toolkitTypeSwift, toolkitTypeKotlin}
+
 //go:embed templates/*
 var templates embed.FS
 
@@ -33,6 +40,18 @@ type GoToolkitInventoryModel struct {
 	PackageID string
 }
 
+// SwiftToolkitInventoryModel ...
+type SwiftToolkitInventoryModel struct {
+	// ExecutableName: name of the Swift package's executable target (product)
+	ExecutableName string
+}
+
+// KotlinToolkitInventoryModel ...
+type KotlinToolkitInventoryModel struct {
+	// ExecutableName: name of the Gradle application (start script) built from the Kotlin project
+	ExecutableName string
+}
+
 // InventoryModel ...
 type InventoryModel struct {
 	Author         string
@@ -46,35 +65,107 @@ type InventoryModel struct {
 	SourceCodeURL string
 	SupportURL    string
 	//
-	ToolkitType        string
-	GoToolkitInventory GoToolkitInventoryModel
+	ToolkitType            string
+	GoToolkitInventory     GoToolkitInventoryModel
+	SwiftToolkitInventory  SwiftToolkitInventoryModel
+	KotlinToolkitInventory KotlinToolkitInventoryModel
 	//
 	Year int
 }
 
+// AnswersModel holds the answers for the step creation questions,
+// provided up front with command line flags or an answers file.
+// Empty values are asked interactively, or reported as missing in no-input mode.
+type AnswersModel struct {
+	Author      string `yaml:"author,omitempty"`
+	Title       string `yaml:"title,omitempty"`
+	ID          string `yaml:"id,omitempty"`
+	Summary     string `yaml:"summary,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	TypeTag     string `yaml:"type_tag,omitempty"`
+	Toolkit     string `yaml:"toolkit,omitempty"`
+	SourceURL   string `yaml:"source_url,omitempty"`
+	SupportURL  string `yaml:"support_url,omitempty"`
+	GoPackageID string `yaml:"go_package_id,omitempty"`
+	Dir         string `yaml:"dir,omitempty"`
+}
+
+// ReadAnswersFile ...
+func ReadAnswersFile(pth string) (AnswersModel, error) {
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return AnswersModel{}, errors.Wrapf(err, "Failed to read answers file (%s)", pth)
+	}
+
+	var answers AnswersModel
+	if err := yaml.UnmarshalStrict(bytes, &answers); err != nil {
+		return AnswersModel{}, errors.Wrapf(err, "Failed to parse answers file (%s)", pth)
+	}
+	return answers, nil
+}
+
+// Merge returns the answers overridden by the non empty values of other.
+func (answers AnswersModel) Merge(other AnswersModel) AnswersModel {
+	override := func(value *string, otherValue string) {
+		if otherValue != "" {
+			*value = otherValue
+		}
+	}
+
+	override(&answers.Author, other.Author)
+	override(&answers.Title, other.Title)
+	override(&answers.ID, other.ID)
+	override(&answers.Summary, other.Summary)
+	override(&answers.Description, other.Description)
+	override(&answers.TypeTag, other.TypeTag)
+	override(&answers.Toolkit, other.Toolkit)
+	override(&answers.SourceURL, other.SourceURL)
+	override(&answers.SupportURL, other.SupportURL)
+	override(&answers.GoPackageID, other.GoPackageID)
+	override(&answers.Dir, other.Dir)
+	return answers
+}
+
 // Step ...
-func Step() error {
-	inventoryForCreateStep := InventoryModel{
-		Author:         "",
-		Title:          "",
-		ID:             "",
-		Summary:        "",
-		Description:    "",
-		PrimaryTypeTag: "",
-		//
-		WebsiteURL:    "",
-		SourceCodeURL: "",
-		SupportURL:    "",
-		//
-		ToolkitType: toolkitTypeBash,
-		GoToolkitInventory: GoToolkitInventoryModel{
-			PackageID: "",
-		},
-		//
-		Year: time.Now().Year(),
+// Every value provided in answers is used as is, the rest is asked interactively.
+// If isNoInput is true nothing is asked: values with a sensible default
+// (author, toolkit, ID, support URL, directory) fall back to it,
+// and every other missing value is reported at once in the returned error.
+func Step(answers AnswersModel, isNoInput bool) error {
+	inventoryForCreateStep, err := inventoryFromAnswers(answers)
+	if err != nil {
+		return err
 	}
+	stepDir := answers.Dir
 
-	{
+	if isNoInput {
+		if inventoryForCreateStep.Author == "" {
+			inventoryForCreateStep.Author = readAuthorFromGitConfig()
+		}
+		if inventoryForCreateStep.ToolkitType == "" {
+			inventoryForCreateStep.ToolkitType = toolkitTypeBash
+			inventoryForCreateStep = fillDerivedValues(inventoryForCreateStep)
+		}
+
+		if missing := missingAnswers(inventoryForCreateStep); len(missing) > 0 {
+			return errors.Errorf("Missing required values (no input mode): %s", strings.Join(missing, ", "))
+		}
+
+		if stepDir == "" {
+			stepDir, err = defaultStepDir(inventoryForCreateStep)
+			if err != nil {
+				return errors.Wrap(err, "Failed to determine default step directory")
+			}
+		}
+		stepDirAbsPth, err := pathutil.AbsPath(stepDir)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get absolute path for step directory (%s)", stepDir)
+		}
+
+		return createStep(inventoryForCreateStep, stepDirAbsPth)
+	}
+
+	if inventoryForCreateStep.Author == "" {
 		defaultAuthor := readAuthorFromGitConfig()
 		author, err := goinp.AskForStringWithDefault(colorstring.Green("Who are you / who's the author?"), defaultAuthor)
 		if err != nil {
@@ -83,7 +174,7 @@ func Step() error {
 		inventoryForCreateStep.Author = author
 	}
 
-	{
+	if inventoryForCreateStep.Title == "" {
 		title, err := goinp.AskForString(colorstring.Green("What's the title / name of the Step?"))
 		if err != nil {
 			return errors.Wrap(err, "Failed to determine title")
@@ -91,20 +182,20 @@ func Step() error {
 		inventoryForCreateStep.Title = title
 	}
 
-	{
+	if inventoryForCreateStep.ID == "" {
 		id := generateIDFromString(inventoryForCreateStep.Title)
 		printInfoLine("Generated Step ID (from provided Title):", id)
 		inventoryForCreateStep.ID = id
 	}
 
-	{
+	if inventoryForCreateStep.Summary == "" {
 		summary, err := goinp.AskForString(colorstring.Green("Please provide a summary"))
 		if err != nil {
 			return errors.Wrap(err, "Failed to determine summary")
 		}
 		inventoryForCreateStep.Summary = summary
 	}
-	{
+	if inventoryForCreateStep.Description == "" {
 		description, err := goinp.AskForString(colorstring.Green("Please provide a description"))
 		if err != nil {
 			return errors.Wrap(err, "Failed to determine description")
@@ -112,23 +203,16 @@ func Step() error {
 		inventoryForCreateStep.Description = description
 	}
 
-	{
-		// available primary categories / type_tags:
-		// https://github.com/bitrise-io/bitrise/blob/master/_docs/step-development-guideline.md#step-grouping-convention
+	if inventoryForCreateStep.PrimaryTypeTag == "" {
 		fmt.Println()
-		primaryTypeTag, err := goinp.SelectFromStrings(colorstring.Green("What's the primary category of this Step?"), []string{
-			"access-control", "artifact-info",
-			"installer", "deploy",
-			"utility", "dependency", "code-sign",
-			"build", "test", "notification",
-		})
+		primaryTypeTag, err := goinp.SelectFromStrings(colorstring.Green("What's the primary category of this Step?"), audit.TypeTags)
 		if err != nil {
 			return errors.Wrap(err, "Failed to determine primary category")
 		}
 		inventoryForCreateStep.PrimaryTypeTag = primaryTypeTag
 	}
 
-	{
+	if inventoryForCreateStep.ToolkitType == "" {
 		fmt.Println()
 		fmt.Println("Toolkit: the entry/base language of the Step.")
 		fmt.Println("Our recommendation is to use Bash for very simple Steps")
@@ -138,16 +222,14 @@ func Step() error {
 		fmt.Println("Note: Of course even if you select e.g. Bash as the entry language, you can run other scripts from there,")
 		fmt.Println(" so it's possible to write the majority of the step's code in e.g. Ruby,")
 		fmt.Println(" and have an entry Bash script which does nothing else except running the Ruby script.")
-		toolkitType, err := goinp.SelectFromStrings(colorstring.Green("Which toolkit (language) would you like to use?"), []string{
-			toolkitTypeBash, toolkitTypeGo,
-		})
+		toolkitType, err := goinp.SelectFromStrings(colorstring.Green("Which toolkit (language) would you like to use?"), toolkitTypes)
 		if err != nil {
 			return errors.Wrap(err, "Failed to determine the toolkit")
 		}
 		inventoryForCreateStep.ToolkitType = toolkitType
 	}
 
-	{
+	if inventoryForCreateStep.SourceCodeURL == "" {
 		fmt.Println()
 		fmt.Println("Website & source code URL:")
 		isGitHub, err := goinp.AskForBoolWithDefault(colorstring.Green("Will you host the source code on GitHub?"), true)
@@ -184,10 +266,12 @@ func Step() error {
 
 		inventoryForCreateStep.WebsiteURL = websiteURL
 		inventoryForCreateStep.SourceCodeURL = websiteURL
-		inventoryForCreateStep.SupportURL = supportURL
+		if inventoryForCreateStep.SupportURL == "" {
+			inventoryForCreateStep.SupportURL = supportURL
+		}
 	}
 
-	if inventoryForCreateStep.ToolkitType == toolkitTypeGo {
+	if inventoryForCreateStep.ToolkitType == toolkitTypeGo && inventoryForCreateStep.GoToolkitInventory.PackageID == "" {
 		if goPkgID, err := goutil.ParsePackageNameFromURL(inventoryForCreateStep.SourceCodeURL); err != nil {
 			fmt.Println()
 			fmt.Println(" [!] Failed to parse Go package ID from URL, error:", err)
@@ -208,24 +292,123 @@ func Step() error {
 		}
 	}
 
-	stepDirAbsPth, err := defaultStepDir(inventoryForCreateStep)
-	if err != nil {
-		return errors.Wrap(err, "Failed to determine default step directory")
-	}
-	fmt.Println()
-	fmt.Println("Where should the step directory be created?")
-	customDir, err := goinp.AskForStringWithDefault(colorstring.Green("Step directory"), stepDirAbsPth)
-	if err != nil {
-		return errors.Wrap(err, "Failed to determine step directory")
+	inventoryForCreateStep = fillDerivedValues(inventoryForCreateStep)
+
+	if stepDir == "" {
+		stepDirAbsPth, err := defaultStepDir(inventoryForCreateStep)
+		if err != nil {
+			return errors.Wrap(err, "Failed to determine default step directory")
+		}
+		fmt.Println()
+		fmt.Println("Where should the step directory be created?")
+		stepDir, err = goinp.AskForStringWithDefault(colorstring.Green("Step directory"), stepDirAbsPth)
+		if err != nil {
+			return errors.Wrap(err, "Failed to determine step directory")
+		}
 	}
-	stepDirAbsPth, err = pathutil.AbsPath(customDir)
+	stepDirAbsPth, err := pathutil.AbsPath(stepDir)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to get absolute path for step directory (%s)", customDir)
+		return errors.Wrapf(err, "Failed to get absolute path for step directory (%s)", stepDir)
 	}
 
 	return createStep(inventoryForCreateStep, stepDirAbsPth)
 }
 
+// inventoryFromAnswers fills the inventory with the provided answers
+// and the values which can be derived from them.
+// Values which were not answered are left empty.
+func inventoryFromAnswers(answers AnswersModel) (InventoryModel, error) {
+	if answers.TypeTag != "" && !slices.Contains(audit.TypeTags, answers.TypeTag) {
+		return InventoryModel{}, errors.Errorf("Invalid type tag (%s), available: %s", answers.TypeTag, strings.Join(audit.TypeTags, ", "))
+	}
+	if answers.Toolkit != "" && !slices.Contains(toolkitTypes, answers.Toolkit) {
+		return InventoryModel{}, errors.Errorf("Invalid toolkit (%s), available: %s", answers.Toolkit, strings.Join(toolkitTypes, ", "))
+	}
+
+	inventory := InventoryModel{
+		Author:         answers.Author,
+		Title:          answers.Title,
+		ID:             answers.ID,
+		Summary:        answers.Summary,
+		Description:    answers.Description,
+		PrimaryTypeTag: answers.TypeTag,
+		//
+		WebsiteURL:    answers.SourceURL,
+		SourceCodeURL: answers.SourceURL,
+		SupportURL:    answers.SupportURL,
+		//
+		ToolkitType: answers.Toolkit,
+		GoToolkitInventory: GoToolkitInventoryModel{
+			PackageID: answers.GoPackageID,
+		},
+		//
+		Year: time.Now().Year(),
+	}
+
+	return fillDerivedValues(inventory), nil
+}
+
+// fillDerivedValues sets the empty values of the inventory which
+// can be derived from other, already provided values.
+func fillDerivedValues(inventory InventoryModel) InventoryModel {
+	if inventory.ID == "" && inventory.Title != "" {
+		inventory.ID = generateIDFromString(inventory.Title)
+	}
+
+	if inventory.SupportURL == "" && inventory.SourceCodeURL != "" {
+		inventory.SupportURL = inventory.SourceCodeURL
+		if strings.HasPrefix(inventory.SourceCodeURL, "https://github.com/") {
+			inventory.SupportURL = strings.TrimSuffix(inventory.SourceCodeURL, "/") + "/issues"
+		}
+	}
+
+	if inventory.ToolkitType == toolkitTypeGo && inventory.GoToolkitInventory.PackageID == "" && inventory.SourceCodeURL != "" {
+		if goPkgID, err := goutil.ParsePackageNameFromURL(inventory.SourceCodeURL); err == nil {
+			inventory.GoToolkitInventory.PackageID = goPkgID
+		}
+	}
+
+	if inventory.ID != "" {
+		if inventory.ToolkitType == toolkitTypeSwift && inventory.SwiftToolkitInventory.ExecutableName == "" {
+			inventory.SwiftToolkitInventory.ExecutableName = inventory.ID
+		}
+		if inventory.ToolkitType == toolkitTypeKotlin && inventory.KotlinToolkitInventory.ExecutableName == "" {
+			inventory.KotlinToolkitInventory.ExecutableName = inventory.ID
+		}
+	}
+
+	return inventory
+}
+
+// missingAnswers returns the names (flag names) of the values
+// which are required for creating the step but are not yet set.
+func missingAnswers(inventory InventoryModel) []string {
+	var missing []string
+	for _, value := range []struct {
+		Name  string
+		Value string
+	}{
+		{Name: "author", Value: inventory.Author},
+		{Name: "title", Value: inventory.Title},
+		{Name: "id", Value: inventory.ID},
+		{Name: "summary", Value: inventory.Summary},
+		{Name: "description", Value: inventory.Description},
+		{Name: "type-tag", Value: inventory.PrimaryTypeTag},
+		{Name: "toolkit", Value: inventory.ToolkitType},
+		{Name: "source-url", Value: inventory.SourceCodeURL},
+	} {
+		if value.Value == "" {
+			missing = append(missing, value.Name)
+		}
+	}
+
+	if inventory.ToolkitType == toolkitTypeGo && inventory.GoToolkitInventory.PackageID == "" {
+		missing = append(missing, "go-package-id")
+	}
+
+	return missing
+}
+
 func readAuthorFromGitConfig() string {
 	userName, err := command.New("git", "config", "user.name").RunAndReturnTrimmedOutput()
 	if err != nil {
@@ -315,6 +498,33 @@ func createStep(inventory InventoryModel, stepDirAbsPth string) error {
 			FilePath:      filepath.Join(stepDirAbsPth, "main.go"),
 			ToolkitFilter: toolkitTypeGo,
 		},
+		// Toolkit: Swift
+		{
+			TemplatePath:  "swift/Package.swift.gotemplate",
+			FilePath:      filepath.Join(stepDirAbsPth, "Package.swift"),
+			ToolkitFilter: toolkitTypeSwift,
+		},
+		{
+			TemplatePath:  "swift/main.swift.gotemplate",
+			FilePath:      filepath.Join(stepDirAbsPth, "Sources", inventory.SwiftToolkitInventory.ExecutableName, "main.swift"),
+			ToolkitFilter: toolkitTypeSwift,
+		},
+		// Toolkit: Kotlin
+		{
+			TemplatePath:  "kotlin/settings.gradle.kts.gotemplate",
+			FilePath:      filepath.Join(stepDirAbsPth, "settings.gradle.kts"),
+			ToolkitFilter: toolkitTypeKotlin,
+		},
+		{
+			TemplatePath:  "kotlin/build.gradle.kts.gotemplate",
+			FilePath:      filepath.Join(stepDirAbsPth, "build.gradle.kts"),
+			ToolkitFilter: toolkitTypeKotlin,
+		},
+		{
+			TemplatePath:  "kotlin/Main.kt.gotemplate",
+			FilePath:      filepath.Join(stepDirAbsPth, "src", "main", "kotlin", "Main.kt"),
+			ToolkitFilter: toolkitTypeKotlin,
+		},
 	} {
 		if aTemplate.ToolkitFilter != "" && aTemplate.ToolkitFilter != inventory.ToolkitType {
 			// skip
@@ -383,6 +593,9 @@ func evaluateTemplateAndWriteToFile(filePth, templatePth string, inventory Inven
 		return errors.Wrap(err, "Failed to evaluate template")
 	}
 
+	if err := os.MkdirAll(filepath.Dir(filePth), 0755); err != nil {
+		return errors.Wrapf(err, "Failed to create directory for file (%s)", filePth)
+	}
 	if err := fileutil.WriteStringToFile(filePth, evaluatedContent); err != nil {
 		return errors.Wrapf(err, "Failed to write evaluated template into file (%s)", filePth)
 	}
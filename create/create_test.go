@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/stepman/stepman"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,4 +65,129 @@ func Test_evaluateTemplate(t *testing.T) {
   go:
     package_name: github.com/bitrise-io/bitrise-step-ut-test-step`)
 	}
+
+	t.Log("Swift toolkit - step.yml template test")
+	{
+		evaluatedContent, err := evaluateTemplate("step.yml.gotemplate", InventoryModel{
+			ID:          invID,
+			ToolkitType: toolkitTypeSwift,
+			SwiftToolkitInventory: SwiftToolkitInventoryModel{
+				ExecutableName: invID,
+			},
+		})
+		require.NoError(t, err)
+		require.Contains(t, evaluatedContent, `toolkit:
+  swift:
+    executable_name: ut-test-step`)
+	}
+
+	t.Log("Kotlin toolkit - step.yml template test")
+	{
+		evaluatedContent, err := evaluateTemplate("step.yml.gotemplate", InventoryModel{
+			ID:          invID,
+			ToolkitType: toolkitTypeKotlin,
+			KotlinToolkitInventory: KotlinToolkitInventoryModel{
+				ExecutableName: invID,
+			},
+		})
+		require.NoError(t, err)
+		require.Contains(t, evaluatedContent, `toolkit:
+  kotlin:
+    executable_name: ut-test-step`)
+	}
+
+	t.Log("Swift toolkit - gitignore template test")
+	{
+		evaluatedContent, err := evaluateTemplate("gitignore.gotemplate", InventoryModel{ToolkitType: toolkitTypeSwift})
+		require.NoError(t, err)
+		require.Equal(t, ".bitrise*\n.idea\n.vscode\nREADME.md.backup\n_tmp/\n.build/\n.swiftpm/\n", evaluatedContent)
+	}
+}
+
+func Test_createStep(t *testing.T) {
+	for _, toolkitType := range toolkitTypes {
+		t.Log("Toolkit:", toolkitType)
+		{
+			inventory, err := inventoryFromAnswers(AnswersModel{
+				Author:      "UT Author",
+				Title:       "UT Step",
+				Summary:     "UT summary",
+				Description: "UT description",
+				TypeTag:     "test",
+				Toolkit:     toolkitType,
+				SourceURL:   "https://github.com/bitrise-io/bitrise-step-ut-step",
+			})
+			require.NoError(t, err)
+
+			stepDir := filepath.Join(t.TempDir(), "step")
+			require.NoError(t, createStep(inventory, stepDir))
+
+			step, err := stepman.ParseStepDefinition(filepath.Join(stepDir, "step.yml"), false)
+			require.NoError(t, err)
+			require.NotNil(t, step.Toolkit)
+		}
+	}
+}
+
+func Test_inventoryFromAnswers(t *testing.T) {
+	t.Log("Derived values")
+	{
+		inventory, err := inventoryFromAnswers(AnswersModel{
+			Title:     "My Step",
+			Toolkit:   toolkitTypeGo,
+			SourceURL: "https://github.com/bitrise-io/bitrise-step-my-step",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "my-step", inventory.ID)
+		require.Equal(t, "https://github.com/bitrise-io/bitrise-step-my-step", inventory.WebsiteURL)
+		require.Equal(t, "https://github.com/bitrise-io/bitrise-step-my-step/issues", inventory.SupportURL)
+		require.Equal(t, "github.com/bitrise-io/bitrise-step-my-step", inventory.GoToolkitInventory.PackageID)
+	}
+
+	t.Log("Provided values are not overridden")
+	{
+		inventory, err := inventoryFromAnswers(AnswersModel{
+			Title:      "My Step",
+			ID:         "custom-id",
+			SourceURL:  "https://gitlab.com/me/bitrise-step-my-step",
+			SupportURL: "https://example.com/support",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "custom-id", inventory.ID)
+		require.Equal(t, "https://example.com/support", inventory.SupportURL)
+	}
+
+	t.Log("Invalid toolkit")
+	{
+		_, err := inventoryFromAnswers(AnswersModel{Toolkit: "cobol"})
+		require.Error(t, err)
+	}
+
+	t.Log("Invalid type tag")
+	{
+		_, err := inventoryFromAnswers(AnswersModel{TypeTag: "unknown"})
+		require.Error(t, err)
+	}
+}
+
+func Test_missingAnswers(t *testing.T) {
+	require.Equal(t, []string{"author", "title", "id", "summary", "description", "type-tag", "toolkit", "source-url"}, missingAnswers(InventoryModel{}))
+
+	inventory, err := inventoryFromAnswers(AnswersModel{
+		Author:      "UT Author",
+		Title:       "UT Step",
+		Summary:     "UT summary",
+		Description: "UT description",
+		TypeTag:     "test",
+		Toolkit:     toolkitTypeGo,
+		SourceURL:   "https://example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"go-package-id"}, missingAnswers(inventory))
+}
+
+func Test_AnswersModel_Merge(t *testing.T) {
+	fileAnswers := AnswersModel{Title: "From file", Summary: "File summary"}
+	merged := fileAnswers.Merge(AnswersModel{Title: "From flag"})
+	require.Equal(t, AnswersModel{Title: "From flag", Summary: "File summary"}, merged)
 }
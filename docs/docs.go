@@ -0,0 +1,340 @@
+package docs
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/go-utils/templateutil"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+const (
+	// StartMarker marks the beginning of the generated documentation in a README
+	StartMarker = "<!-- bitrise-step-docs:start -->"
+	// EndMarker marks the end of the generated documentation in a README
+	EndMarker = "<!-- bitrise-step-docs:end -->"
+)
+
+const readmeTemplate = "README.md.gotemplate"
+
+//go:embed templates/*
+var templates embed.FS
+
+// InputModel ...
+type InputModel struct {
+	Key          string
+	Title        string
+	Summary      string
+	Description  string
+	DefaultValue string
+	ValueOptions []string
+	IsRequired   bool
+	IsSensitive  bool
+}
+
+// Details returns the description of the input for the inputs table:
+// its title, its summary (or description if it has no summary) and its value options.
+func (input InputModel) Details() string {
+	details := envDetails(input.Title, input.Summary, input.Description)
+	if len(input.ValueOptions) > 0 {
+		var options []string
+		for _, option := range input.ValueOptions {
+			options = append(options, inlineCode(option))
+		}
+		details = append(details, "Options: "+strings.Join(options, ", "))
+	}
+	return strings.Join(details, "<br>")
+}
+
+// OutputModel ...
+type OutputModel struct {
+	Key         string
+	Title       string
+	Summary     string
+	Description string
+}
+
+// Details returns the description of the output for the outputs table.
+func (output OutputModel) Details() string {
+	return strings.Join(envDetails(output.Title, output.Summary, output.Description), "<br>")
+}
+
+func envDetails(title, summary, description string) []string {
+	var details []string
+	if title != "" {
+		details = append(details, "**"+tableCell(title)+"**")
+	}
+	if summary == "" {
+		summary = description
+	}
+	if summary != "" {
+		details = append(details, tableCell(summary))
+	}
+	return details
+}
+
+// DocsModel is the inventory of the README template.
+type DocsModel struct {
+	ID          string
+	Version     string
+	Title       string
+	Summary     string
+	Description string
+	//
+	Inputs  []InputModel
+	Outputs []OutputModel
+	//
+	Toolkit        string
+	ToolkitDetails string
+	BrewDeps       []string
+	AptGetDeps     []string
+	Dependencies   []string
+	// Example is the step's reference in a bitrise.yml workflow, with the required inputs
+	Example string
+}
+
+// NewDocsModel collects the documentation of the step (parsed from its step.yml).
+// The version is optional, if it's set the example references the step with its major version locked.
+func NewDocsModel(step models.StepModel, stepID, version string) (DocsModel, error) {
+	docs := DocsModel{
+		ID:             stepID,
+		Version:        version,
+		Title:          pointers.String(step.Title),
+		Summary:        strings.TrimSpace(pointers.String(step.Summary)),
+		Description:    strings.TrimSpace(pointers.String(step.Description)),
+		Toolkit:        stepmanutil.ToolkitName(step.Toolkit),
+		ToolkitDetails: toolkitDetails(step.Toolkit),
+	}
+	if docs.Title == "" {
+		docs.Title = stepID
+	}
+	if docs.Toolkit == "" {
+		docs.Toolkit = "bash"
+	}
+	if step.Deps != nil {
+		for _, dep := range step.Deps.Brew {
+			docs.BrewDeps = append(docs.BrewDeps, dep.Name)
+		}
+		for _, dep := range step.Deps.AptGet {
+			docs.AptGetDeps = append(docs.AptGetDeps, dep.Name)
+		}
+	}
+	for _, dep := range step.Dependencies {
+		docs.Dependencies = append(docs.Dependencies, dep.Manager+": "+dep.Name)
+	}
+
+	inputs, err := readInputs(step)
+	if err != nil {
+		return DocsModel{}, err
+	}
+	docs.Inputs = inputs
+	for _, env := range step.Outputs {
+		key, _, options, err := readEnv(env)
+		if err != nil {
+			return DocsModel{}, errors.Wrap(err, "Failed to read output")
+		}
+		docs.Outputs = append(docs.Outputs, OutputModel{
+			Key:         key,
+			Title:       pointers.String(options.Title),
+			Summary:     strings.TrimSpace(pointers.String(options.Summary)),
+			Description: strings.TrimSpace(pointers.String(options.Description)),
+		})
+	}
+
+	example, err := exampleUsage(docs)
+	if err != nil {
+		return DocsModel{}, err
+	}
+	docs.Example = example
+	return docs, nil
+}
+
+// Render renders the README documentation of the step.
+func Render(docs DocsModel) (string, error) {
+	bytes, err := templates.ReadFile("templates/" + readmeTemplate)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to read %s template", readmeTemplate)
+	}
+	content, err := templateutil.EvaluateTemplateStringToString(string(bytes), docs, template.FuncMap{
+		"cell": tableCell,
+		"code": inlineCode,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to evaluate template %s", readmeTemplate)
+	}
+	return strings.TrimSpace(content) + "\n", nil
+}
+
+// ReplaceSection replaces the content between the StartMarker and EndMarker comments of the README
+// with the section, and keeps everything else as it is.
+// If the README has no markers, the section is appended to the end of it, between the markers.
+func ReplaceSection(readme, section string) (string, error) {
+	markedSection := StartMarker + "\n" + strings.TrimSpace(section) + "\n" + EndMarker
+
+	startCount, endCount := strings.Count(readme, StartMarker), strings.Count(readme, EndMarker)
+	if startCount == 0 && endCount == 0 {
+		if strings.TrimSpace(readme) == "" {
+			return markedSection + "\n", nil
+		}
+		return strings.TrimRight(readme, "\n") + "\n\n" + markedSection + "\n", nil
+	}
+	if startCount != 1 || endCount != 1 {
+		return "", errors.Errorf("README should contain exactly one %s and one %s marker, found: %d and %d", StartMarker, EndMarker, startCount, endCount)
+	}
+
+	start, end := strings.Index(readme, StartMarker), strings.Index(readme, EndMarker)
+	if end < start {
+		return "", errors.Errorf("%s marker should come after the %s marker", EndMarker, StartMarker)
+	}
+	return readme[:start] + markedSection + readme[end+len(EndMarker):], nil
+}
+
+func readInputs(step models.StepModel) ([]InputModel, error) {
+	var inputs []InputModel
+	for _, env := range step.Inputs {
+		key, value, options, err := readEnv(env)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read input")
+		}
+		inputs = append(inputs, InputModel{
+			Key:          key,
+			Title:        pointers.String(options.Title),
+			Summary:      strings.TrimSpace(pointers.String(options.Summary)),
+			Description:  strings.TrimSpace(pointers.String(options.Description)),
+			DefaultValue: value,
+			ValueOptions: options.ValueOptions,
+			IsRequired:   pointers.BoolWithDefault(options.IsRequired, envmanModels.DefaultIsRequired),
+			IsSensitive:  pointers.BoolWithDefault(options.IsSensitive, envmanModels.DefaultIsSensitive),
+		})
+	}
+	return inputs, nil
+}
+
+func readEnv(env envmanModels.EnvironmentItemModel) (string, string, envmanModels.EnvironmentItemOptionsModel, error) {
+	key, value, err := env.GetKeyValuePair()
+	if err != nil {
+		return "", "", envmanModels.EnvironmentItemOptionsModel{}, err
+	}
+	options, err := env.GetOptions()
+	if err != nil {
+		return "", "", envmanModels.EnvironmentItemOptionsModel{}, errors.Wrapf(err, "Failed to read options of %s", key)
+	}
+	return key, value, options, nil
+}
+
+// exampleUsage returns the step's reference, as a bitrise.yml workflow step list item,
+// with the required inputs. Sensitive inputs get a secret env var as their value.
+func exampleUsage(docs DocsModel) (string, error) {
+	reference := docs.ID
+	if major := strings.Split(docs.Version, ".")[0]; major != "" {
+		reference += "@" + major
+	}
+
+	lines := []string{"- " + reference + ":"}
+	var requiredInputLines []string
+	for _, input := range docs.Inputs {
+		if !input.IsRequired {
+			continue
+		}
+
+		value := input.DefaultValue
+		if input.IsSensitive {
+			value = secretPlaceholder(input.Key)
+		}
+		if value == "" {
+			value = valuePlaceholder(input.Key)
+		}
+
+		inputLineList, err := inputLines(input.Key, value, "")
+		if err != nil {
+			return "", err
+		}
+		requiredInputLines = append(requiredInputLines, inputLineList...)
+	}
+	if len(requiredInputLines) > 0 {
+		lines = append(lines, "    inputs:")
+		lines = append(lines, requiredInputLines...)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// secretPlaceholder returns the env var reference used as the value of a sensitive input, e.g. $API_KEY.
+func secretPlaceholder(key string) string {
+	return "$" + strings.ToUpper(key)
+}
+
+// valuePlaceholder returns the value of a required input without default value, which has to be filled in, e.g. <app_path>.
+func valuePlaceholder(key string) string {
+	return "<" + key + ">"
+}
+
+// inputLines returns the lines of the input, as an item of a step's input list in a bitrise.yml.
+// Multi-line values are block scalars, their lines are indented below the key.
+// Every line gets the prefix, which is placed after the indentation.
+func inputLines(key, value, prefix string) ([]string, error) {
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to serialize the value of input %s", key)
+	}
+	valueLines := strings.Split(strings.TrimSuffix(string(bytes), "\n"), "\n")
+	lines := []string{"    " + prefix + "- " + key + ": " + valueLines[0]}
+	for _, line := range valueLines[1:] {
+		lines = append(lines, "    "+prefix+"  "+line)
+	}
+	return lines, nil
+}
+
+func toolkitDetails(toolkit *models.StepToolkitModel) string {
+	if toolkit == nil {
+		return ""
+	}
+	switch {
+	case toolkit.Bash != nil && toolkit.Bash.EntryFile != "":
+		return "entry file: " + toolkit.Bash.EntryFile
+	case toolkit.Go != nil:
+		return "package: " + toolkit.Go.PackageName
+	case toolkit.Swift != nil:
+		return "executable: " + toolkit.Swift.ExecutableName
+	case toolkit.Kotlin != nil:
+		return "executable: " + toolkit.Kotlin.ExecutableName
+	}
+	return ""
+}
+
+// tableCell escapes the text to fit in a markdown table cell: pipes are escaped, and line breaks are converted to <br>.
+func tableCell(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// inlineCode formats the value as inline code in a markdown table cell, or returns - for an empty value.
+// A <br> would be rendered literally in a code span, so each line of a multi-line value is a separate code span.
+func inlineCode(value string) string {
+	value = strings.TrimSpace(strings.ReplaceAll(value, "\r\n", "\n"))
+	if value == "" {
+		return "-"
+	}
+
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			lines = append(lines, "")
+		case strings.Contains(line, "`"):
+			lines = append(lines, fmt.Sprintf("`` %s ``", tableCell(line)))
+		default:
+			lines = append(lines, fmt.Sprintf("`%s`", tableCell(line)))
+		}
+	}
+	return strings.Join(lines, "<br>")
+}
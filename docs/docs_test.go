@@ -0,0 +1,107 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocsModel(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/step.yml", false)
+	require.NoError(t, err)
+
+	docs, err := NewDocsModel(step, "deploy-to-store", "2.1.0")
+	require.NoError(t, err)
+
+	require.Equal(t, "Deploy to Store", docs.Title)
+	require.Equal(t, "go", docs.Toolkit)
+	require.Equal(t, "package: github.com/bitrise-steplib/bitrise-step-deploy-to-store", docs.ToolkitDetails)
+	require.Equal(t, []string{"jq"}, docs.BrewDeps)
+	require.Equal(t, []string{"jq", "zip"}, docs.AptGetDeps)
+
+	require.Equal(t, 4, len(docs.Inputs))
+	require.Equal(t, InputModel{
+		Key:          "api_key",
+		Title:        "API key",
+		ValueOptions: []string{},
+		IsRequired:   true,
+		IsSensitive:  true,
+	}, docs.Inputs[1])
+	require.Equal(t, "**Track**<br>Options: `internal`, `beta`, `production`", docs.Inputs[2].Details())
+	require.Equal(t, "**Release notes**<br>Notes of the release.<br>Shown in the store.", docs.Inputs[3].Details())
+	require.Equal(t, "**App path**<br>Path of the app \\| apk or aab", docs.Inputs[0].Details())
+
+	require.Equal(t, 1, len(docs.Outputs))
+	require.Equal(t, "STORE_RELEASE_URL", docs.Outputs[0].Key)
+
+	require.Equal(t, `- deploy-to-store@2:
+    inputs:
+    - app_path: $BITRISE_APK_PATH
+    - api_key: $API_KEY`, docs.Example)
+}
+
+func TestExampleUsage(t *testing.T) {
+	example, err := exampleUsage(DocsModel{ID: "script"})
+	require.NoError(t, err)
+	require.Equal(t, "- script:", example)
+
+	example, err = exampleUsage(DocsModel{ID: "script", Version: "1.1.0", Inputs: []InputModel{
+		{Key: "content", DefaultValue: "#!/bin/bash\necho hello", IsRequired: true},
+		{Key: "working_dir", IsRequired: true},
+		{Key: "is_debug", DefaultValue: "no"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, `- script@1:
+    inputs:
+    - content: |-
+        #!/bin/bash
+        echo hello
+    - working_dir: <working_dir>`, example)
+}
+
+func TestRender(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/step.yml", false)
+	require.NoError(t, err)
+	docs, err := NewDocsModel(step, "deploy-to-store", "")
+	require.NoError(t, err)
+
+	content, err := Render(docs)
+	require.NoError(t, err)
+
+	require.Contains(t, content, "# Deploy to Store\n\nUploads the app to the store\n\n## Description\n\nUploads the built app to the store.\n\nRequires an API key.\n")
+	require.Contains(t, content, "```yaml\n- deploy-to-store:\n    inputs:\n")
+	require.Contains(t, content, "| `app_path` | **App path**<br>Path of the app \\| apk or aab | `$BITRISE_APK_PATH` | yes | no |\n")
+	require.Contains(t, content, "| `api_key` | **API key** | - | yes | yes |\n")
+	require.Contains(t, content, "| `STORE_RELEASE_URL` | **Release URL**<br>URL of the release in the store |\n")
+	require.Contains(t, content, "- Toolkit: go (package: github.com/bitrise-steplib/bitrise-step-deploy-to-store)\n- Homebrew: `jq`\n- apt-get: `jq`, `zip`\n")
+}
+
+func TestReplaceSection(t *testing.T) {
+	const section = "# My Step\n\nGenerated.\n"
+
+	got, err := ReplaceSection("", section)
+	require.NoError(t, err)
+	require.Equal(t, StartMarker+"\n# My Step\n\nGenerated.\n"+EndMarker+"\n", got)
+
+	got, err = ReplaceSection("# Intro\n\nHand written.\n", section)
+	require.NoError(t, err)
+	require.Equal(t, "# Intro\n\nHand written.\n\n"+StartMarker+"\n# My Step\n\nGenerated.\n"+EndMarker+"\n", got)
+
+	got, err = ReplaceSection("Before\n"+StartMarker+"\nOld docs\n"+EndMarker+"\nAfter\n", section)
+	require.NoError(t, err)
+	require.Equal(t, "Before\n"+StartMarker+"\n# My Step\n\nGenerated.\n"+EndMarker+"\nAfter\n", got)
+
+	_, err = ReplaceSection("Before\n"+StartMarker+"\nOld docs\n", section)
+	require.Error(t, err)
+
+	_, err = ReplaceSection(EndMarker+"\nOld docs\n"+StartMarker+"\n", section)
+	require.Error(t, err)
+}
+
+func TestInlineCode(t *testing.T) {
+	require.Equal(t, "-", inlineCode(""))
+	require.Equal(t, "`$BITRISE_APK_PATH`", inlineCode("$BITRISE_APK_PATH"))
+	require.Equal(t, "`` echo `date` ``", inlineCode("echo `date`"))
+	require.Equal(t, "`#!/bin/bash`<br>`set -e`<br><br>`echo a \\| tee b`", inlineCode("#!/bin/bash\r\nset -e\n\necho a | tee b\n"))
+}
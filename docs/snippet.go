@@ -0,0 +1,60 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/bitrise-io/stepman/models"
+)
+
+// Snippet returns the step reference as a bitrise.yml workflow step list item, with every input of the step.
+// Required and sensitive inputs are set: to their default value, sensitive ones to a secret env var (e.g. $API_KEY),
+// required ones without default value to a placeholder (e.g. <app_path>).
+// The other inputs are commented out with their default value, so the snippet does not change the step's behavior.
+// Every input is preceded by a comment with its title, its flags (required, sensitive) and its value options.
+func Snippet(reference string, step models.StepModel) (string, error) {
+	inputs, err := readInputs(step)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{"- " + reference + ":"}
+	if len(inputs) > 0 {
+		lines = append(lines, "    inputs:")
+	}
+	for _, input := range inputs {
+		title := input.Title
+		if title == "" {
+			title = input.Key
+		}
+		var flags []string
+		if input.IsRequired {
+			flags = append(flags, "required")
+		}
+		if input.IsSensitive {
+			flags = append(flags, "sensitive")
+		}
+		if len(flags) > 0 {
+			title += " (" + strings.Join(flags, ", ") + ")"
+		}
+		lines = append(lines, "    # "+strings.ReplaceAll(title, "\n", " "))
+		if len(input.ValueOptions) > 0 {
+			lines = append(lines, "    # options: "+strings.Join(input.ValueOptions, ", "))
+		}
+
+		value, prefix := input.DefaultValue, "# "
+		if input.IsSensitive {
+			value, prefix = secretPlaceholder(input.Key), ""
+		} else if input.IsRequired {
+			prefix = ""
+			if value == "" {
+				value = valuePlaceholder(input.Key)
+			}
+		}
+		valueLines, err := inputLines(input.Key, value, prefix)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, valueLines...)
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
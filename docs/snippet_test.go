@@ -0,0 +1,59 @@
+package docs
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnippet(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/step.yml", false)
+	require.NoError(t, err)
+
+	snippet, err := Snippet("deploy-to-store@2", step)
+	require.NoError(t, err)
+	require.Equal(t, `- deploy-to-store@2:
+    inputs:
+    # App path (required)
+    - app_path: $BITRISE_APK_PATH
+    # API key (required, sensitive)
+    - api_key: $API_KEY
+    # Track
+    # options: internal, beta, production
+    # - track: internal
+    # Release notes
+    # - notes: ""
+`, snippet)
+
+	snippet, err = Snippet("script@1", models.StepModel{Inputs: []envmanModels.EnvironmentItemModel{
+		{"content": "", envmanModels.OptionsKey: envmanModels.EnvironmentItemOptionsModel{IsRequired: pointers.NewBoolPtr(true)}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, `- script@1:
+    inputs:
+    # content (required)
+    - content: <content>
+`, snippet)
+
+	snippet, err = Snippet("script@1", models.StepModel{Inputs: step.Inputs[:0]})
+	require.NoError(t, err)
+	require.Equal(t, "- script@1:\n", snippet)
+}
+
+func TestInputLines(t *testing.T) {
+	lines, err := inputLines("content", "#!/bin/bash\necho \"hello\"", "# ")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"    # - content: |-",
+		"    #     #!/bin/bash",
+		"    #     echo \"hello\"",
+	}, lines)
+
+	lines, err = inputLines("is_debug", "yes", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{`    - is_debug: "yes"`}, lines)
+}
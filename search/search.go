@@ -0,0 +1,175 @@
+package search
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+)
+
+// Weights of the step properties, a match in a property with higher weight
+// makes the step more relevant.
+const (
+	idWeight             = 10
+	titleWeight          = 6
+	typeTagWeight        = 4
+	projectTypeTagWeight = 3
+	summaryWeight        = 3
+	inputKeyWeight       = 2
+	descriptionWeight    = 1
+
+	// exactMatchMultiplier is applied if a whole word (or the whole id) matches the term
+	exactMatchMultiplier = 2
+)
+
+// FilterModel ...
+type FilterModel struct {
+	TypeTag string
+	// ProjectType filters the steps to the ones which can be used for the project type:
+	// steps without project_type_tags can be used for any project type.
+	ProjectType       string
+	IncludeDeprecated bool
+}
+
+// ResultModel is a step matching the search query.
+type ResultModel struct {
+	Collection      string   `json:"collection"`
+	ID              string   `json:"id"`
+	Version         string   `json:"version"`
+	Title           string   `json:"title"`
+	Summary         string   `json:"summary"`
+	TypeTags        []string `json:"type_tags,omitempty"`
+	ProjectTypeTags []string `json:"project_type_tags,omitempty"`
+	IsDeprecated    bool     `json:"is_deprecated"`
+	Score           int      `json:"score"`
+}
+
+// Collection searches the latest version of every step of the collection.
+// A step matches the query if every term (whitespace separated word) of the query matches
+// any of its id, title, summary, description, type_tags, project_type_tags or input keys.
+// The results are not sorted, see Sort.
+func Collection(collectionID string, collection models.StepCollectionModel, query string, filter FilterModel) []ResultModel {
+	terms := strings.Fields(strings.ToLower(query))
+
+	var results []ResultModel
+	for id, stepGroup := range collection.Steps {
+		isDeprecated := stepGroup.Info.DeprecateNotes != "" || stepGroup.Info.RemovalDate != ""
+		if isDeprecated && !filter.IncludeDeprecated {
+			continue
+		}
+
+		step, found := stepGroup.LatestVersion()
+		if !found {
+			continue
+		}
+		if filter.TypeTag != "" && !slices.Contains(step.TypeTags, filter.TypeTag) {
+			continue
+		}
+		if filter.ProjectType != "" && len(step.ProjectTypeTags) > 0 && !slices.Contains(step.ProjectTypeTags, filter.ProjectType) {
+			continue
+		}
+
+		score := Score(id, step, terms)
+		if score == 0 {
+			continue
+		}
+
+		results = append(results, ResultModel{
+			Collection:      collectionID,
+			ID:              id,
+			Version:         stepGroup.LatestVersionNumber,
+			Title:           pointers.String(step.Title),
+			Summary:         pointers.String(step.Summary),
+			TypeTags:        step.TypeTags,
+			ProjectTypeTags: step.ProjectTypeTags,
+			IsDeprecated:    isDeprecated,
+			Score:           score,
+		})
+	}
+	return results
+}
+
+// Sort sorts the results by relevance, the most relevant first.
+// Results with the same relevance are sorted by id, then by collection.
+func Sort(results []ResultModel) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].ID != results[j].ID {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].Collection < results[j].Collection
+	})
+}
+
+// Score returns the relevance of the step for the (lowercase) search terms,
+// or 0 if any of the terms does not match the step.
+func Score(id string, step models.StepModel, terms []string) int {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	type field struct {
+		weight int
+		values []string
+	}
+	fields := []field{
+		{idWeight, []string{id}},
+		{titleWeight, []string{pointers.String(step.Title)}},
+		{typeTagWeight, step.TypeTags},
+		{projectTypeTagWeight, step.ProjectTypeTags},
+		{summaryWeight, []string{pointers.String(step.Summary)}},
+		{inputKeyWeight, inputKeys(step)},
+		{descriptionWeight, []string{pointers.String(step.Description)}},
+	}
+
+	total := 0
+	for _, term := range terms {
+		termScore := 0
+		for _, f := range fields {
+			for _, value := range f.values {
+				termScore += f.weight * matchScore(strings.ToLower(value), term)
+			}
+		}
+		if termScore == 0 {
+			return 0
+		}
+		total += termScore
+	}
+	return total
+}
+
+// matchScore returns 0 if the value does not contain the term,
+// exactMatchMultiplier if a whole word of the value is the term, 1 otherwise.
+func matchScore(value, term string) int {
+	if !strings.Contains(value, term) {
+		return 0
+	}
+	isSeparator := func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}
+	for _, word := range strings.FieldsFunc(value, isSeparator) {
+		if word == term {
+			return exactMatchMultiplier
+		}
+	}
+	if value == term {
+		return exactMatchMultiplier
+	}
+	return 1
+}
+
+func inputKeys(step models.StepModel) []string {
+	var keys []string
+	for _, input := range step.Inputs {
+		key, _, err := input.GetKeyValuePair()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollection() models.StepCollectionModel {
+	stepGroup := func(info models.StepGroupInfoModel, step models.StepModel) models.StepGroupModel {
+		return models.StepGroupModel{
+			Info:                info,
+			LatestVersionNumber: "1.0.0",
+			Versions:            map[string]models.StepModel{"1.0.0": step},
+		}
+	}
+
+	return models.StepCollectionModel{
+		Steps: models.StepHash{
+			"amazon-s3-deploy": stepGroup(models.StepGroupInfoModel{}, models.StepModel{
+				Title:    pointers.NewStringPtr("Amazon S3 Deploy"),
+				Summary:  pointers.NewStringPtr("Uploads the build artifacts to an S3 bucket"),
+				TypeTags: []string{"deploy"},
+				Inputs:   []envmanModels.EnvironmentItemModel{{"bucket_name": ""}},
+			}),
+			"generic-file-storage": stepGroup(models.StepGroupInfoModel{}, models.StepModel{
+				Title:       pointers.NewStringPtr("Generic File Storage"),
+				Summary:     pointers.NewStringPtr("Downloads files"),
+				Description: pointers.NewStringPtr("Downloads files, e.g. from an S3 bucket"),
+				TypeTags:    []string{"utility"},
+			}),
+			"xcode-archive": stepGroup(models.StepGroupInfoModel{}, models.StepModel{
+				Title:           pointers.NewStringPtr("Xcode Archive"),
+				Summary:         pointers.NewStringPtr("Archives and exports the app"),
+				TypeTags:        []string{"build"},
+				ProjectTypeTags: []string{"ios"},
+			}),
+			"old-s3-upload": stepGroup(models.StepGroupInfoModel{DeprecateNotes: "Use amazon-s3-deploy"}, models.StepModel{
+				Title:    pointers.NewStringPtr("S3 upload"),
+				TypeTags: []string{"deploy"},
+			}),
+		},
+	}
+}
+
+func resultIDs(results []ResultModel) []string {
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	return ids
+}
+
+func TestCollection(t *testing.T) {
+	collection := testCollection()
+	search := func(query string, filter FilterModel) []string {
+		results := Collection("lib", collection, query, filter)
+		Sort(results)
+		return resultIDs(results)
+	}
+
+	t.Log("ranked by relevance")
+	{
+		require.Equal(t, []string{"amazon-s3-deploy", "generic-file-storage"}, search("s3", FilterModel{}))
+	}
+
+	t.Log("every term has to match")
+	{
+		require.Equal(t, []string{"amazon-s3-deploy"}, search("S3 bucket_name", FilterModel{}))
+		require.Empty(t, search("s3 xcode", FilterModel{}))
+	}
+
+	t.Log("deprecated steps")
+	{
+		require.Equal(t, []string{"amazon-s3-deploy", "old-s3-upload", "generic-file-storage"}, search("s3", FilterModel{IncludeDeprecated: true}))
+	}
+
+	t.Log("type tag filter")
+	{
+		require.Equal(t, []string{"generic-file-storage"}, search("s3", FilterModel{TypeTag: "utility"}))
+	}
+
+	t.Log("project type filter - steps without project type tags match any project type")
+	{
+		require.Equal(t, []string{"xcode-archive"}, search("archive", FilterModel{ProjectType: "ios"}))
+		require.Empty(t, search("archive", FilterModel{ProjectType: "android"}))
+		require.Equal(t, []string{"generic-file-storage"}, search("downloads", FilterModel{ProjectType: "android"}))
+	}
+}
+
+func TestScore(t *testing.T) {
+	step := models.StepModel{
+		Title:   pointers.NewStringPtr("Git Clone"),
+		Summary: pointers.NewStringPtr("Clones the repository"),
+	}
+
+	require.Equal(t, 0, Score("git-clone", step, nil))
+	require.Equal(t, 0, Score("git-clone", step, []string{"svn"}))
+	// whole word matches in the id and title, partial match in the summary
+	require.Equal(t, idWeight*exactMatchMultiplier+titleWeight*exactMatchMultiplier+summaryWeight, Score("git-clone", step, []string{"clone"}))
+	require.Equal(t, idWeight*exactMatchMultiplier, Score("git-clone", step, []string{"git-clone"}))
+}
@@ -0,0 +1,228 @@
+package share
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// StepIDEnvKey ...
+	StepIDEnvKey = "BITRISE_STEP_ID"
+	// StepVersionEnvKey ...
+	StepVersionEnvKey = "BITRISE_STEP_VERSION"
+	// StepGitCloneURLEnvKey ...
+	StepGitCloneURLEnvKey = "BITRISE_STEP_GIT_CLONE_URL"
+	// StepLibForkGitURLEnvKey ...
+	StepLibForkGitURLEnvKey = "MY_STEPLIB_REPO_FORK_GIT_URL"
+
+	secretsFileName = ".bitrise.secrets.yml"
+)
+
+// ParamsModel holds the parameters of sharing a step version into a StepLib.
+type ParamsModel struct {
+	StepID         string
+	Version        string
+	GitCloneURL    string
+	StepLibForkURL string
+}
+
+// Merge returns the params overridden by the non empty values of other.
+func (params ParamsModel) Merge(other ParamsModel) ParamsModel {
+	if other.StepID != "" {
+		params.StepID = other.StepID
+	}
+	if other.Version != "" {
+		params.Version = other.Version
+	}
+	if other.GitCloneURL != "" {
+		params.GitCloneURL = other.GitCloneURL
+	}
+	if other.StepLibForkURL != "" {
+		params.StepLibForkURL = other.StepLibForkURL
+	}
+	return params
+}
+
+// Validate returns an error listing every missing param.
+// The StepLib fork URL is only required if isForkRequired is true.
+func (params ParamsModel) Validate(isForkRequired bool) error {
+	var missing []string
+	if params.StepID == "" {
+		missing = append(missing, StepIDEnvKey)
+	}
+	if params.Version == "" {
+		missing = append(missing, StepVersionEnvKey)
+	}
+	if params.GitCloneURL == "" {
+		missing = append(missing, StepGitCloneURLEnvKey)
+	}
+	if isForkRequired && params.StepLibForkURL == "" {
+		missing = append(missing, StepLibForkGitURLEnvKey)
+	}
+
+	if len(missing) > 0 {
+		return errors.Errorf("Missing share params: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ReadParamsFromBitriseYML reads the share params from the app envs of the step's bitrise.yml.
+// Env values are expanded with the process environment and the envs
+// of the .bitrise.secrets.yml next to the bitrise.yml (if exists).
+func ReadParamsFromBitriseYML(pth string) (ParamsModel, error) {
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return ParamsModel{}, errors.Wrapf(err, "Failed to read bitrise.yml (%s)", pth)
+	}
+
+	var config bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal(bytes, &config); err != nil {
+		return ParamsModel{}, errors.Wrapf(err, "Failed to parse bitrise.yml (%s)", pth)
+	}
+
+	secrets, err := readSecrets(filepath.Join(filepath.Dir(pth), secretsFileName))
+	if err != nil {
+		return ParamsModel{}, err
+	}
+	expand := func(value string) string {
+		return os.Expand(value, func(key string) string {
+			if value, ok := os.LookupEnv(key); ok {
+				return value
+			}
+			return secrets[key]
+		})
+	}
+
+	appEnvs, err := envsToMap(config.App.Environments)
+	if err != nil {
+		return ParamsModel{}, errors.Wrapf(err, "Invalid app envs in bitrise.yml (%s)", pth)
+	}
+
+	return ParamsModel{
+		StepID:         expand(appEnvs[StepIDEnvKey]),
+		Version:        expand(appEnvs[StepVersionEnvKey]),
+		GitCloneURL:    expand(appEnvs[StepGitCloneURLEnvKey]),
+		StepLibForkURL: expand(appEnvs[StepLibForkGitURLEnvKey]),
+	}, nil
+}
+
+func readSecrets(pth string) (map[string]string, error) {
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return nil, errors.Wrapf(err, "Failed to check if secrets file (%s) exists", pth)
+	} else if !exist {
+		return map[string]string{}, nil
+	}
+
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read secrets file (%s)", pth)
+	}
+
+	var secrets envmanModels.EnvsSerializeModel
+	if err := yaml.Unmarshal(bytes, &secrets); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse secrets file (%s)", pth)
+	}
+
+	return envsToMap(secrets.Envs)
+}
+
+func envsToMap(envs []envmanModels.EnvironmentItemModel) (map[string]string, error) {
+	envMap := map[string]string{}
+	for _, env := range envs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return nil, err
+		}
+		envMap[key] = value
+	}
+	return envMap, nil
+}
+
+// StepLibEntryModel is a step version entry, as it is integrated into a StepLib.
+type StepLibEntryModel struct {
+	// Path of the step.yml, relative to the StepLib's root
+	Path    string
+	Content string
+	// StepInfoPath is set if the step is new in the StepLib,
+	// in which case a step-info.yml is created for it too
+	StepInfoPath    string
+	StepInfoContent string
+}
+
+// GenerateStepLibEntry generates the StepLib entry of the step version described by params,
+// from the step's step.yml. The step's source commit is the given commit.
+// If isNewStep is true the StepLib entry includes the default step-info.yml too.
+func GenerateStepLibEntry(stepYMLPth string, params ParamsModel, commit string, isNewStep bool) (StepLibEntryModel, error) {
+	step, err := readStepModel(stepYMLPth)
+	if err != nil {
+		return StepLibEntryModel{}, err
+	}
+
+	step.Source = &models.StepSourceModel{
+		Git:    params.GitCloneURL,
+		Commit: commit,
+	}
+	step.PublishedAt = pointers.NewTimePtr(time.Now())
+
+	content, err := yaml.Marshal(step)
+	if err != nil {
+		return StepLibEntryModel{}, errors.Wrap(err, "Failed to serialize step")
+	}
+
+	entry := StepLibEntryModel{
+		Path:    filepath.Join("steps", params.StepID, params.Version, "step.yml"),
+		Content: string(content),
+	}
+
+	if isNewStep {
+		content, err := yaml.Marshal(models.StepGroupInfoModel{
+			Maintainer: "community",
+		})
+		if err != nil {
+			return StepLibEntryModel{}, errors.Wrap(err, "Failed to serialize step info")
+		}
+
+		entry.StepInfoPath = filepath.Join("steps", params.StepID, "step-info.yml")
+		entry.StepInfoContent = string(content)
+	}
+
+	return entry, nil
+}
+
+// ReadTagCommit returns the commit hash of the given tag in the step's git repository.
+func ReadTagCommit(stepDir, tag string) (string, error) {
+	cmd := command.New("git", "rev-list", "-n", "1", "refs/tags/"+tag, "--").SetDir(stepDir)
+	out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+	if err != nil {
+		return "", errors.Errorf("No tag (%s) found in the git repository (%s)", tag, stepDir)
+	}
+	return out, nil
+}
+
+func readStepModel(stepYMLPth string) (models.StepModel, error) {
+	bytes, err := fileutil.ReadBytesFromFile(stepYMLPth)
+	if err != nil {
+		return models.StepModel{}, errors.Wrapf(err, "Failed to read step.yml (%s)", stepYMLPth)
+	}
+
+	var step models.StepModel
+	if err := yaml.Unmarshal(bytes, &step); err != nil {
+		return models.StepModel{}, errors.Wrapf(err, "Failed to parse step.yml (%s)", stepYMLPth)
+	}
+	if err := step.Normalize(); err != nil {
+		return models.StepModel{}, errors.Wrapf(err, "Failed to normalize step.yml (%s)", stepYMLPth)
+	}
+	return step, nil
+}
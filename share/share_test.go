@@ -0,0 +1,67 @@
+package share
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadParamsFromBitriseYML(t *testing.T) {
+	// the fork URL can be set in the environment, t.Setenv restores it after the test
+	t.Setenv("MY_STEPLIB_REPO_FORK_GIT_URL", "")
+	require.NoError(t, os.Unsetenv("MY_STEPLIB_REPO_FORK_GIT_URL"))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bitrise.yml"), []byte(`format_version: 4
+app:
+  envs:
+  - BITRISE_STEP_ID: my-step
+  - BITRISE_STEP_VERSION: "0.0.1"
+  - BITRISE_STEP_GIT_CLONE_URL: https://github.com/me/bitrise-step-my-step.git
+  - MY_STEPLIB_REPO_FORK_GIT_URL: $MY_STEPLIB_REPO_FORK_GIT_URL
+`), 0600))
+
+	t.Log("Unresolved fork URL")
+	{
+		params, err := ReadParamsFromBitriseYML(filepath.Join(dir, "bitrise.yml"))
+		require.NoError(t, err)
+		require.Equal(t, ParamsModel{
+			StepID:      "my-step",
+			Version:     "0.0.1",
+			GitCloneURL: "https://github.com/me/bitrise-step-my-step.git",
+		}, params)
+		require.NoError(t, params.Validate(false))
+		require.EqualError(t, params.Validate(true), "Missing share params: MY_STEPLIB_REPO_FORK_GIT_URL")
+	}
+
+	t.Log("Fork URL from secrets")
+	{
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".bitrise.secrets.yml"), []byte(`envs:
+- MY_STEPLIB_REPO_FORK_GIT_URL: https://github.com/me/bitrise-steplib.git
+`), 0600))
+
+		params, err := ReadParamsFromBitriseYML(filepath.Join(dir, "bitrise.yml"))
+		require.NoError(t, err)
+		require.Equal(t, "https://github.com/me/bitrise-steplib.git", params.StepLibForkURL)
+	}
+}
+
+func TestGenerateStepLibEntry(t *testing.T) {
+	params := ParamsModel{
+		StepID:      "my-step",
+		Version:     "1.0.0",
+		GitCloneURL: "https://github.com/me/bitrise-step-my-step.git",
+	}
+
+	entry, err := GenerateStepLibEntry(filepath.Join("testdata", "step.yml"), params, "abc123", true)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("steps", "my-step", "1.0.0", "step.yml"), entry.Path)
+	require.Contains(t, entry.Content, `source:
+  git: https://github.com/me/bitrise-step-my-step.git
+  commit: abc123`)
+	require.Contains(t, entry.Content, "published_at:")
+	require.Equal(t, filepath.Join("steps", "my-step", "step-info.yml"), entry.StepInfoPath)
+	require.Equal(t, "maintainer: community\n", entry.StepInfoContent)
+}
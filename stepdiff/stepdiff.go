@@ -0,0 +1,307 @@
+package stepdiff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// ChangeKind ...
+type ChangeKind string
+
+const (
+	// ChangeKindAdded ...
+	ChangeKindAdded ChangeKind = "added"
+	// ChangeKindRemoved ...
+	ChangeKindRemoved ChangeKind = "removed"
+	// ChangeKindChanged ...
+	ChangeKindChanged ChangeKind = "changed"
+)
+
+// ChangeModel is a difference between two versions of a step.
+type ChangeModel struct {
+	Kind ChangeKind `json:"kind"`
+	// Subject is what the change is about: "input (key)", "output (key)", "toolkit", "deps", ...
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+	// IsBreaking is true if the workflows using the old version
+	// might have to be changed to use the new one
+	IsBreaking bool `json:"is_breaking"`
+}
+
+// String ...
+func (change ChangeModel) String() string {
+	return fmt.Sprintf("%s: %s", change.Subject, change.Message)
+}
+
+// HasBreakingChange ...
+func HasBreakingChange(changes []ChangeModel) bool {
+	for _, change := range changes {
+		if change.IsBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Steps compares the two versions of a step and returns the changes from the old to the new one.
+// The steps are expected to have the missing defaults filled (see models.StepModel.FillMissingDefaults).
+// Breaking changes are:
+// - removed inputs and outputs
+// - new required inputs without default value
+// - inputs becoming required without default value
+// - removed value options
+// Outputs have no required flag, default value and value options, only their removal is breaking.
+func Steps(from, to models.StepModel) ([]ChangeModel, error) {
+	var changes []ChangeModel
+	add := func(kind ChangeKind, isBreaking bool, subject, format string, args ...interface{}) {
+		changes = append(changes, ChangeModel{Kind: kind, Subject: subject, Message: fmt.Sprintf(format, args...), IsBreaking: isBreaking})
+	}
+
+	inputChanges, err := envs("input", from.Inputs, to.Inputs)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to compare inputs")
+	}
+	changes = append(changes, inputChanges...)
+
+	outputChanges, err := envs("output", from.Outputs, to.Outputs)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to compare outputs")
+	}
+	changes = append(changes, outputChanges...)
+
+	fromToolkit, toToolkit := stepmanutil.ToolkitName(from.Toolkit), stepmanutil.ToolkitName(to.Toolkit)
+	if fromToolkit != toToolkit {
+		add(ChangeKindChanged, false, "toolkit", "%s -> %s", displayValue(fromToolkit), displayValue(toToolkit))
+	} else if fromDetails, toDetails := toolkitDetails(from.Toolkit), toolkitDetails(to.Toolkit); fromDetails != toDetails {
+		add(ChangeKindChanged, false, "toolkit", "%s -> %s", displayValue(fromDetails), displayValue(toDetails))
+	}
+
+	compareLists := func(subject string, fromItems, toItems []string) {
+		for _, item := range toItems {
+			if !slices.Contains(fromItems, item) {
+				add(ChangeKindAdded, false, subject, "%s", item)
+			}
+		}
+		for _, item := range fromItems {
+			if !slices.Contains(toItems, item) {
+				add(ChangeKindRemoved, false, subject, "%s", item)
+			}
+		}
+	}
+	compareLists("deps (brew)", brewDeps(from.Deps), brewDeps(to.Deps))
+	compareLists("deps (apt_get)", aptGetDeps(from.Deps), aptGetDeps(to.Deps))
+	compareLists("dependencies", dependencies(from.Dependencies), dependencies(to.Dependencies))
+
+	compareValues := func(subject, fromValue, toValue string) {
+		if fromValue != toValue {
+			add(ChangeKindChanged, false, subject, "%s -> %s", displayValue(fromValue), displayValue(toValue))
+		}
+	}
+	compareValues("run_if", pointers.String(from.RunIf), pointers.String(to.RunIf))
+	compareValues("is_always_run", boolString(from.IsAlwaysRun), boolString(to.IsAlwaysRun))
+	compareValues("is_skippable", boolString(from.IsSkippable), boolString(to.IsSkippable))
+	compareValues("timeout", intString(from.Timeout), intString(to.Timeout))
+	compareValues("no_output_timeout", intString(from.NoOutputTimeout), intString(to.NoOutputTimeout))
+
+	return changes, nil
+}
+
+// envInfo is the comparable form of a step input or output
+type envInfo struct {
+	key          string
+	value        string
+	options      envmanModels.EnvironmentItemOptionsModel
+	isRequired   bool
+	valueOptions []string
+}
+
+func readEnvs(envs []envmanModels.EnvironmentItemModel) ([]envInfo, error) {
+	var infos []envInfo
+	for _, env := range envs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return nil, err
+		}
+		options, err := env.GetOptions()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, envInfo{
+			key:          key,
+			value:        value,
+			options:      options,
+			isRequired:   pointers.BoolWithDefault(options.IsRequired, envmanModels.DefaultIsRequired),
+			valueOptions: options.ValueOptions,
+		})
+	}
+	return infos, nil
+}
+
+func findEnv(envs []envInfo, key string) (envInfo, bool) {
+	for _, env := range envs {
+		if env.key == key {
+			return env, true
+		}
+	}
+	return envInfo{}, false
+}
+
+func envs(kind string, fromEnvs, toEnvs []envmanModels.EnvironmentItemModel) ([]ChangeModel, error) {
+	from, err := readEnvs(fromEnvs)
+	if err != nil {
+		return nil, err
+	}
+	to, err := readEnvs(toEnvs)
+	if err != nil {
+		return nil, err
+	}
+
+	// the required flag, the default value and the value options are only meaningful for inputs
+	isInput := kind == "input"
+
+	var changes []ChangeModel
+	add := func(changeKind ChangeKind, isBreaking bool, key, format string, args ...interface{}) {
+		changes = append(changes, ChangeModel{
+			Kind:       changeKind,
+			Subject:    fmt.Sprintf("%s (%s)", kind, key),
+			Message:    fmt.Sprintf(format, args...),
+			IsBreaking: isBreaking,
+		})
+	}
+
+	for _, fromEnv := range from {
+		if _, found := findEnv(to, fromEnv.key); !found {
+			add(ChangeKindRemoved, true, fromEnv.key, "removed")
+		}
+	}
+
+	for _, toEnv := range to {
+		fromEnv, found := findEnv(from, toEnv.key)
+		if !found {
+			if !isInput {
+				add(ChangeKindAdded, false, toEnv.key, "added")
+			} else if toEnv.isRequired && toEnv.value == "" {
+				add(ChangeKindAdded, true, toEnv.key, "new required %s without default value", kind)
+			} else if toEnv.value != "" {
+				add(ChangeKindAdded, false, toEnv.key, "added, default: %s", toEnv.value)
+			} else {
+				add(ChangeKindAdded, false, toEnv.key, "added")
+			}
+			continue
+		}
+
+		if isInput {
+			if fromEnv.value != toEnv.value {
+				add(ChangeKindChanged, false, toEnv.key, "default value: %s -> %s", displayValue(fromEnv.value), displayValue(toEnv.value))
+			}
+
+			if !fromEnv.isRequired && toEnv.isRequired {
+				add(ChangeKindChanged, toEnv.value == "", toEnv.key, "became required")
+			} else if fromEnv.isRequired && !toEnv.isRequired {
+				add(ChangeKindChanged, false, toEnv.key, "is not required anymore")
+			}
+
+			for _, option := range fromEnv.valueOptions {
+				if !slices.Contains(toEnv.valueOptions, option) {
+					add(ChangeKindRemoved, true, toEnv.key, "value option removed: %s", option)
+				}
+			}
+			for _, option := range toEnv.valueOptions {
+				if !slices.Contains(fromEnv.valueOptions, option) {
+					add(ChangeKindAdded, false, toEnv.key, "value option added: %s", option)
+				}
+			}
+		}
+
+		compareOption := func(name string, fromOption, toOption *bool, defaultValue bool) {
+			fromValue := pointers.BoolWithDefault(fromOption, defaultValue)
+			toValue := pointers.BoolWithDefault(toOption, defaultValue)
+			if fromValue != toValue {
+				add(ChangeKindChanged, false, toEnv.key, "%s: %t -> %t", name, fromValue, toValue)
+			}
+		}
+		compareOption("is_sensitive", fromEnv.options.IsSensitive, toEnv.options.IsSensitive, envmanModels.DefaultIsSensitive)
+		compareOption("is_expand", fromEnv.options.IsExpand, toEnv.options.IsExpand, envmanModels.DefaultIsExpand)
+		compareOption("is_dont_change_value", fromEnv.options.IsDontChangeValue, toEnv.options.IsDontChangeValue, envmanModels.DefaultIsDontChangeValue)
+		compareOption("is_template", fromEnv.options.IsTemplate, toEnv.options.IsTemplate, envmanModels.DefaultIsTemplate)
+		compareOption("skip_if_empty", fromEnv.options.SkipIfEmpty, toEnv.options.SkipIfEmpty, envmanModels.DefaultSkipIfEmpty)
+	}
+
+	return changes, nil
+}
+
+func toolkitDetails(toolkit *models.StepToolkitModel) string {
+	if toolkit == nil {
+		return ""
+	}
+	switch {
+	case toolkit.Bash != nil:
+		return "entry_file: " + toolkit.Bash.EntryFile
+	case toolkit.Go != nil:
+		return "package_name: " + toolkit.Go.PackageName
+	case toolkit.Swift != nil:
+		return "executable_name: " + toolkit.Swift.ExecutableName + ", binary_location: " + toolkit.Swift.BinaryLocation
+	case toolkit.Kotlin != nil:
+		return "executable_name: " + toolkit.Kotlin.ExecutableName
+	}
+	return ""
+}
+
+func brewDeps(deps *models.DepsModel) []string {
+	if deps == nil {
+		return nil
+	}
+	var names []string
+	for _, dep := range deps.Brew {
+		names = append(names, dep.Name)
+	}
+	return names
+}
+
+func aptGetDeps(deps *models.DepsModel) []string {
+	if deps == nil {
+		return nil
+	}
+	var names []string
+	for _, dep := range deps.AptGet {
+		names = append(names, dep.Name)
+	}
+	return names
+}
+
+func dependencies(deps []models.DependencyModel) []string {
+	var names []string
+	for _, dep := range deps {
+		names = append(names, dep.Manager+": "+dep.Name)
+	}
+	return names
+}
+
+func boolString(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t", *value)
+}
+
+func intString(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *value)
+}
+
+func displayValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,63 @@
+package stepdiff
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/stretchr/testify/require"
+)
+
+func parseStep(t *testing.T, pth string) models.StepModel {
+	step, err := stepman.ParseStepDefinition(pth, false)
+	require.NoError(t, err)
+	return step
+}
+
+func TestSteps(t *testing.T) {
+	from := parseStep(t, "testdata/from.yml")
+	to := parseStep(t, "testdata/to.yml")
+
+	t.Log("No changes")
+	{
+		changes, err := Steps(from, from)
+		require.NoError(t, err)
+		require.Empty(t, changes)
+		require.False(t, HasBreakingChange(changes))
+	}
+
+	t.Log("Changes")
+	{
+		changes, err := Steps(from, to)
+		require.NoError(t, err)
+		require.Equal(t, []ChangeModel{
+			{Kind: ChangeKindRemoved, Subject: "input (removed_input)", Message: "removed", IsBreaking: true},
+			{Kind: ChangeKindChanged, Subject: "input (mode)", Message: "default value: fast -> slow"},
+			{Kind: ChangeKindRemoved, Subject: "input (mode)", Message: "value option removed: medium", IsBreaking: true},
+			{Kind: ChangeKindAdded, Subject: "input (mode)", Message: "value option added: turbo"},
+			{Kind: ChangeKindChanged, Subject: "input (optional_input)", Message: "became required", IsBreaking: true},
+			{Kind: ChangeKindChanged, Subject: "input (verbose)", Message: "is_expand: true -> false"},
+			{Kind: ChangeKindAdded, Subject: "input (api_token)", Message: "new required input without default value", IsBreaking: true},
+			{Kind: ChangeKindAdded, Subject: "input (new_input)", Message: "added, default: default"},
+			{Kind: ChangeKindRemoved, Subject: "output (REMOVED_OUTPUT)", Message: "removed", IsBreaking: true},
+			{Kind: ChangeKindAdded, Subject: "output (NEW_OUTPUT)", Message: "added"},
+			{Kind: ChangeKindChanged, Subject: "toolkit", Message: "bash -> go"},
+			{Kind: ChangeKindAdded, Subject: "deps (brew)", Message: "yq"},
+			{Kind: ChangeKindRemoved, Subject: "deps (brew)", Message: "jq"},
+			{Kind: ChangeKindChanged, Subject: "is_always_run", Message: "false -> true"},
+		}, changes)
+		require.True(t, HasBreakingChange(changes))
+	}
+
+	t.Log("Toolkit details change is not breaking")
+	{
+		changed := parseStep(t, "testdata/from.yml")
+		changed.Toolkit = &models.StepToolkitModel{Bash: &models.BashStepToolkitModel{EntryFile: "main.sh"}}
+
+		changes, err := Steps(from, changed)
+		require.NoError(t, err)
+		require.Equal(t, []ChangeModel{
+			{Kind: ChangeKindChanged, Subject: "toolkit", Message: "entry_file: step.sh -> entry_file: main.sh"},
+		}, changes)
+	}
+}
@@ -0,0 +1,153 @@
+package steplist
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/pkg/errors"
+
+	"github.com/bitrise-io/bitrise-plugins-step/stepmanutil"
+)
+
+// Sort keys
+const (
+	SortByID         = "id"
+	SortByTitle      = "title"
+	SortByMaintainer = "maintainer"
+	// SortByPublishedAt sorts the most recently updated steps first
+	SortByPublishedAt = "published_at"
+)
+
+// SortKeys are the available sort keys.
+var SortKeys = []string{SortByID, SortByTitle, SortByMaintainer, SortByPublishedAt}
+
+// ItemModel describes the latest version of a step in a collection.
+type ItemModel struct {
+	Collection     string     `json:"collection" yaml:"collection"`
+	ID             string     `json:"id" yaml:"id"`
+	LatestVersion  string     `json:"latest_version" yaml:"latest_version"`
+	Title          string     `json:"title" yaml:"title"`
+	Summary        string     `json:"summary" yaml:"summary"`
+	TypeTags       []string   `json:"type_tags,omitempty" yaml:"type_tags,omitempty"`
+	HostOsTags     []string   `json:"host_os_tags,omitempty" yaml:"host_os_tags,omitempty"`
+	Toolkit        string     `json:"toolkit,omitempty" yaml:"toolkit,omitempty"`
+	Maintainer     string     `json:"maintainer,omitempty" yaml:"maintainer,omitempty"`
+	PublishedAt    *time.Time `json:"published_at,omitempty" yaml:"published_at,omitempty"`
+	IsDeprecated   bool       `json:"is_deprecated" yaml:"is_deprecated"`
+	DeprecateNotes string     `json:"deprecate_notes,omitempty" yaml:"deprecate_notes,omitempty"`
+	RemovalDate    string     `json:"removal_date,omitempty" yaml:"removal_date,omitempty"`
+}
+
+// FilterModel ...
+type FilterModel struct {
+	TypeTag string
+	// HostOS matches the host_os_tags with the given prefix (e.g. osx matches osx-10.10),
+	// steps without host_os_tags match any host OS.
+	HostOS string
+	// Toolkit matches the steps with the given toolkit, steps without toolkit are run with bash.
+	Toolkit string
+}
+
+// Match ...
+func (filter FilterModel) Match(item ItemModel) bool {
+	if filter.TypeTag != "" && !slices.Contains(item.TypeTags, filter.TypeTag) {
+		return false
+	}
+	if filter.HostOS != "" && len(item.HostOsTags) > 0 {
+		if !slices.ContainsFunc(item.HostOsTags, func(tag string) bool { return strings.HasPrefix(tag, filter.HostOS) }) {
+			return false
+		}
+	}
+	if filter.Toolkit != "" {
+		toolkit := item.Toolkit
+		if toolkit == "" {
+			toolkit = "bash"
+		}
+		if toolkit != filter.Toolkit {
+			return false
+		}
+	}
+	return true
+}
+
+// Items returns the latest version of the steps of the collection, which match the filter.
+// The items are not sorted, see Sort.
+func Items(collectionID string, collection models.StepCollectionModel, filter FilterModel) []ItemModel {
+	var items []ItemModel
+	for id, stepGroup := range collection.Steps {
+		step, found := stepGroup.LatestVersion()
+		if !found {
+			continue
+		}
+
+		item := ItemModel{
+			Collection:     collectionID,
+			ID:             id,
+			LatestVersion:  stepGroup.LatestVersionNumber,
+			Title:          pointers.String(step.Title),
+			Summary:        pointers.String(step.Summary),
+			TypeTags:       step.TypeTags,
+			HostOsTags:     step.HostOsTags,
+			Toolkit:        stepmanutil.ToolkitName(step.Toolkit),
+			Maintainer:     stepGroup.Info.Maintainer,
+			PublishedAt:    step.PublishedAt,
+			IsDeprecated:   stepGroup.Info.DeprecateNotes != "" || stepGroup.Info.RemovalDate != "",
+			DeprecateNotes: stepGroup.Info.DeprecateNotes,
+			RemovalDate:    stepGroup.Info.RemovalDate,
+		}
+		if filter.Match(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Sort sorts the items by the given key (see SortKeys), items with the same key are sorted by id,
+// then by collection (the same step can be in multiple collections).
+func Sort(items []ItemModel, key string) error {
+	var less func(a, b ItemModel) bool
+	switch key {
+	case "", SortByID:
+		less = func(a, b ItemModel) bool { return false }
+	case SortByTitle:
+		less = func(a, b ItemModel) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case SortByMaintainer:
+		less = func(a, b ItemModel) bool { return a.Maintainer < b.Maintainer }
+	case SortByPublishedAt:
+		less = func(a, b ItemModel) bool {
+			return pointers.TimeWithDefault(a.PublishedAt, time.Time{}).After(pointers.TimeWithDefault(b.PublishedAt, time.Time{}))
+		}
+	default:
+		return errors.Errorf("Invalid sort key (%s), available: %s", key, strings.Join(SortKeys, ", "))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if less(items[i], items[j]) {
+			return true
+		}
+		if less(items[j], items[i]) {
+			return false
+		}
+		if items[i].ID != items[j].ID {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].Collection < items[j].Collection
+	})
+	return nil
+}
+
+// RemovalTime returns the removal date of the step, if it's specified in a valid (2006-01-02) format.
+func (item ItemModel) RemovalTime() (time.Time, bool) {
+	if item.RemovalDate == "" {
+		return time.Time{}, false
+	}
+	removal, err := time.Parse("2006-01-02", item.RemovalDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return removal, true
+}
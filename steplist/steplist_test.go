@@ -0,0 +1,119 @@
+package steplist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollection() models.StepCollectionModel {
+	stepGroup := func(info models.StepGroupInfoModel, step models.StepModel) models.StepGroupModel {
+		return models.StepGroupModel{
+			Info:                info,
+			LatestVersionNumber: "1.0.0",
+			Versions:            map[string]models.StepModel{"1.0.0": step},
+		}
+	}
+
+	return models.StepCollectionModel{
+		Steps: models.StepHash{
+			"script": stepGroup(models.StepGroupInfoModel{Maintainer: "bitrise"}, models.StepModel{
+				Title:       pointers.NewStringPtr("Script"),
+				TypeTags:    []string{"utility"},
+				PublishedAt: pointers.NewTimePtr(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}),
+			"xcode-archive": stepGroup(models.StepGroupInfoModel{Maintainer: "bitrise"}, models.StepModel{
+				Title:       pointers.NewStringPtr("Xcode Archive"),
+				TypeTags:    []string{"build"},
+				HostOsTags:  []string{"osx-10.10"},
+				Toolkit:     &models.StepToolkitModel{Go: &models.GoStepToolkitModel{PackageName: "xcode-archive"}},
+				PublishedAt: pointers.NewTimePtr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}),
+			"android-build": stepGroup(models.StepGroupInfoModel{Maintainer: "community", DeprecateNotes: "Use gradle-runner"}, models.StepModel{
+				Title:      pointers.NewStringPtr("Android Build"),
+				TypeTags:   []string{"build"},
+				HostOsTags: []string{"ubuntu"},
+				Toolkit:    &models.StepToolkitModel{Bash: &models.BashStepToolkitModel{EntryFile: "step.sh"}},
+			}),
+		},
+	}
+}
+
+func itemIDs(items []ItemModel) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestItems(t *testing.T) {
+	collection := testCollection()
+	list := func(filter FilterModel) []string {
+		items := Items("lib", collection, filter)
+		require.NoError(t, Sort(items, SortByID))
+		return itemIDs(items)
+	}
+
+	require.Equal(t, []string{"android-build", "script", "xcode-archive"}, list(FilterModel{}))
+	require.Equal(t, []string{"android-build", "xcode-archive"}, list(FilterModel{TypeTag: "build"}))
+
+	t.Log("steps without host_os_tags match any host OS")
+	{
+		require.Equal(t, []string{"script", "xcode-archive"}, list(FilterModel{HostOS: "osx"}))
+	}
+
+	t.Log("steps without toolkit are bash steps")
+	{
+		require.Equal(t, []string{"android-build", "script"}, list(FilterModel{Toolkit: "bash"}))
+		require.Equal(t, []string{"xcode-archive"}, list(FilterModel{Toolkit: "go"}))
+	}
+
+	t.Log("item details")
+	{
+		items := Items("lib", collection, FilterModel{TypeTag: "build", HostOS: "ubuntu"})
+		require.Equal(t, 1, len(items))
+		require.Equal(t, "community", items[0].Maintainer)
+		require.Equal(t, "bash", items[0].Toolkit)
+		require.True(t, items[0].IsDeprecated)
+		require.Equal(t, "Use gradle-runner", items[0].DeprecateNotes)
+	}
+}
+
+func TestSort(t *testing.T) {
+	items := Items("lib", testCollection(), FilterModel{})
+
+	require.NoError(t, Sort(items, SortByTitle))
+	require.Equal(t, []string{"android-build", "script", "xcode-archive"}, itemIDs(items))
+
+	require.NoError(t, Sort(items, SortByMaintainer))
+	require.Equal(t, []string{"script", "xcode-archive", "android-build"}, itemIDs(items))
+
+	require.NoError(t, Sort(items, SortByPublishedAt))
+	require.Equal(t, []string{"xcode-archive", "script", "android-build"}, itemIDs(items))
+
+	t.Log("the same step from multiple collections is sorted by collection")
+	{
+		items := append(Items("lib-b", testCollection(), FilterModel{TypeTag: "utility"}), Items("lib-a", testCollection(), FilterModel{TypeTag: "utility"})...)
+		require.NoError(t, Sort(items, SortByID))
+		require.Equal(t, "lib-a", items[0].Collection)
+		require.Equal(t, "lib-b", items[1].Collection)
+	}
+
+	require.EqualError(t, Sort(items, "size"), "Invalid sort key (size), available: id, title, maintainer, published_at")
+}
+
+func TestItemModel_RemovalTime(t *testing.T) {
+	removal, ok := ItemModel{RemovalDate: "2024-06-10"}.RemovalTime()
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), removal)
+
+	_, ok = ItemModel{}.RemovalTime()
+	require.False(t, ok)
+
+	_, ok = ItemModel{RemovalDate: "soon"}.RemovalTime()
+	require.False(t, ok)
+}
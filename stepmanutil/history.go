@@ -0,0 +1,88 @@
+package stepmanutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bitrise-io/stepman/models"
+)
+
+// VersionHistoryItemModel describes a version of a step, as it is shared in the StepLib.
+type VersionHistoryItemModel struct {
+	Version      string     `json:"version"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	SourceGit    string     `json:"source_git,omitempty"`
+	SourceCommit string     `json:"source_commit,omitempty"`
+	IsLatest     bool       `json:"is_latest"`
+	IsDeprecated bool       `json:"is_deprecated"`
+}
+
+// StepVersionHistory returns every version of the step in the collection, highest first.
+// Versions are marked as deprecated if the step itself is deprecated in the collection.
+func StepVersionHistory(collection models.StepCollectionModel, stepID string) ([]VersionHistoryItemModel, error) {
+	stepGroup, found := collection.Steps[stepID]
+	if !found {
+		return nil, fmt.Errorf("no step found for ID: %s", stepID)
+	}
+
+	versions := make([]string, 0, len(stepGroup.Versions))
+	for version := range stepGroup.Versions {
+		versions = append(versions, version)
+	}
+	SortVersions(versions)
+
+	isDeprecated := stepGroup.Info.DeprecateNotes != "" || stepGroup.Info.RemovalDate != ""
+
+	history := make([]VersionHistoryItemModel, 0, len(versions))
+	for _, version := range versions {
+		step := stepGroup.Versions[version]
+		item := VersionHistoryItemModel{
+			Version:      version,
+			PublishedAt:  step.PublishedAt,
+			IsLatest:     version == stepGroup.LatestVersionNumber,
+			IsDeprecated: isDeprecated,
+		}
+		if step.Source != nil {
+			item.SourceGit = step.Source.Git
+			item.SourceCommit = step.Source.Commit
+		}
+		history = append(history, item)
+	}
+	return history, nil
+}
+
+// FilterVersionHistorySince returns the versions released after the given version or date.
+// since is either a version (8, 8.1 or 8.1.0), in which case the higher versions are returned,
+// or a date (2006-01-02 or RFC3339), in which case the versions published at or after it are returned.
+func FilterVersionHistorySince(history []VersionHistoryItemModel, since string) ([]VersionHistoryItemModel, error) {
+	if sinceTime, ok := parseDate(since); ok {
+		var filtered []VersionHistoryItemModel
+		for _, item := range history {
+			if item.PublishedAt != nil && !item.PublishedAt.Before(sinceTime) {
+				filtered = append(filtered, item)
+			}
+		}
+		return filtered, nil
+	}
+
+	if _, ok := parsePartialSemver(since); !ok {
+		return nil, fmt.Errorf("invalid since value (%s): should be a version (e.g. 8.1.0) or a date (e.g. 2006-01-02)", since)
+	}
+
+	var filtered []VersionHistoryItemModel
+	for _, item := range history {
+		if cmp, err := CompareVersions(item.Version, since); err == nil && cmp > 0 {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
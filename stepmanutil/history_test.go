@@ -0,0 +1,112 @@
+package stepmanutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollection() models.StepCollectionModel {
+	published := func(date string) *time.Time {
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			panic(err)
+		}
+		return &t
+	}
+
+	return models.StepCollectionModel{
+		Steps: models.StepHash{
+			"git-clone": models.StepGroupModel{
+				LatestVersionNumber: "8.10.0",
+				Versions: map[string]models.StepModel{
+					"8.2.0":  {PublishedAt: published("2023-02-01"), Source: &models.StepSourceModel{Commit: "c820"}},
+					"8.10.0": {PublishedAt: published("2024-01-01"), Source: &models.StepSourceModel{Commit: "c8100"}},
+					"7.0.0":  {PublishedAt: published("2022-01-01")},
+				},
+			},
+			"old-step": models.StepGroupModel{
+				Info:                models.StepGroupInfoModel{DeprecateNotes: "Use another step"},
+				LatestVersionNumber: "1.0.0",
+				Versions:            map[string]models.StepModel{"1.0.0": {}},
+			},
+		},
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.0.0-beta", "1.2.0", "1.10.0", "0.9.1", "alpha"}
+	SortVersions(versions)
+	require.Equal(t, []string{"1.10.0", "1.2.0", "0.9.1", "1.0.0-beta", "alpha"}, versions)
+}
+
+func TestStepVersionHistory(t *testing.T) {
+	collection := testCollection()
+
+	t.Log("versions are sorted semantically, with the latest marked")
+	{
+		history, err := StepVersionHistory(collection, "git-clone")
+		require.NoError(t, err)
+		require.Equal(t, 3, len(history))
+		require.Equal(t, "8.10.0", history[0].Version)
+		require.True(t, history[0].IsLatest)
+		require.Equal(t, "c8100", history[0].SourceCommit)
+		require.Equal(t, "8.2.0", history[1].Version)
+		require.False(t, history[1].IsLatest)
+		require.Equal(t, "7.0.0", history[2].Version)
+		require.Equal(t, "", history[2].SourceCommit)
+		require.False(t, history[2].IsDeprecated)
+	}
+
+	t.Log("deprecated step")
+	{
+		history, err := StepVersionHistory(collection, "old-step")
+		require.NoError(t, err)
+		require.True(t, history[0].IsDeprecated)
+	}
+
+	t.Log("unknown step")
+	{
+		_, err := StepVersionHistory(collection, "unknown")
+		require.EqualError(t, err, "no step found for ID: unknown")
+	}
+}
+
+func TestFilterVersionHistorySince(t *testing.T) {
+	history, err := StepVersionHistory(testCollection(), "git-clone")
+	require.NoError(t, err)
+
+	versionsOf := func(items []VersionHistoryItemModel) []string {
+		var versions []string
+		for _, item := range items {
+			versions = append(versions, item.Version)
+		}
+		return versions
+	}
+
+	t.Log("since version")
+	{
+		filtered, err := FilterVersionHistorySince(history, "8.2.0")
+		require.NoError(t, err)
+		require.Equal(t, []string{"8.10.0"}, versionsOf(filtered))
+
+		filtered, err = FilterVersionHistorySince(history, "8")
+		require.NoError(t, err)
+		require.Equal(t, []string{"8.10.0", "8.2.0"}, versionsOf(filtered))
+	}
+
+	t.Log("since date")
+	{
+		filtered, err := FilterVersionHistorySince(history, "2023-02-01")
+		require.NoError(t, err)
+		require.Equal(t, []string{"8.10.0", "8.2.0"}, versionsOf(filtered))
+	}
+
+	t.Log("invalid since")
+	{
+		_, err := FilterVersionHistorySince(history, "yesterday")
+		require.Error(t, err)
+	}
+}
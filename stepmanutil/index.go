@@ -0,0 +1,256 @@
+package stepmanutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bitrise-io/stepman/models"
+)
+
+// indexFormatVersion has to be increased if the format of StepLibIndexModel changes,
+// to invalidate the indexes written by earlier versions
+const indexFormatVersion = 2
+
+// IndexCacheDirEnvKey is the environment variable which can be used
+// to override the directory of the StepLib indexes
+const IndexCacheDirEnvKey = "BITRISE_STEP_INDEX_CACHE_DIR"
+
+// StepLibIndexModel is a slim version of a StepLib's spec.json: it only contains
+// the latest version of each step (with every version number), which is enough to list
+// and search the steps and to show the latest version of a step.
+// The index is cached on disk for each collection route, and rebuilt when the spec.json changes.
+type StepLibIndexModel struct {
+	FormatVersion int    `json:"format_version"`
+	URI           string `json:"uri"`
+	// SpecPath, SpecGeneratedAt, SpecModTime and SpecSize identify the spec.json the index was built from
+	SpecPath        string    `json:"spec_path"`
+	SpecGeneratedAt int64     `json:"spec_generated_at"`
+	SpecModTime     time.Time `json:"spec_mod_time"`
+	SpecSize        int64     `json:"spec_size"`
+	// Collection has the latest version of each step only
+	Collection models.StepCollectionModel `json:"collection"`
+	// Versions are every version number of each step, by step ID
+	Versions map[string][]string `json:"versions"`
+}
+
+// NewStepLibIndex builds the index of the spec.json.
+func NewStepLibIndex(uri string, spec models.StepCollectionModel) StepLibIndexModel {
+	index := StepLibIndexModel{
+		FormatVersion: indexFormatVersion,
+		URI:           uri,
+		Collection:    spec,
+		Versions:      map[string][]string{},
+	}
+
+	index.Collection.Steps = models.StepHash{}
+	for id, stepGroup := range spec.Steps {
+		versions := make([]string, 0, len(stepGroup.Versions))
+		for version := range stepGroup.Versions {
+			versions = append(versions, version)
+		}
+		SortVersions(versions)
+		index.Versions[id] = versions
+
+		slimGroup := models.StepGroupModel{
+			Info:                stepGroup.Info,
+			LatestVersionNumber: stepGroup.LatestVersionNumber,
+			Versions:            map[string]models.StepModel{},
+		}
+		if step, found := stepGroup.LatestVersion(); found {
+			slimGroup.Versions[stepGroup.LatestVersionNumber] = step
+		}
+		index.Collection.Steps[id] = slimGroup
+	}
+	return index
+}
+
+// ResolveStepVersion resolves the version constraint of the step, see ResolveVersion.
+func (index StepLibIndexModel) ResolveStepVersion(stepID, constraint string) (VersionResolutionModel, error) {
+	stepGroup, found := index.Collection.Steps[stepID]
+	if !found {
+		return VersionResolutionModel{}, fmt.Errorf("no step found for ID: %s", stepID)
+	}
+
+	resolution, err := ResolveVersion(index.Versions[stepID], stepGroup.LatestVersionNumber, constraint)
+	if err != nil {
+		return VersionResolutionModel{}, fmt.Errorf("no step version found for (ID: %s) (version: %s): %s", stepID, constraint, err)
+	}
+	return resolution, nil
+}
+
+// LatestStepVersion returns the latest version of the step, prepared the same way as by StepVersion.
+func (index StepLibIndexModel) LatestStepVersion(stepID string) (models.StepModel, error) {
+	stepGroup, found := index.Collection.Steps[stepID]
+	if !found {
+		return models.StepModel{}, fmt.Errorf("no step found for ID: %s", stepID)
+	}
+	step, found := stepGroup.LatestVersion()
+	if !found {
+		return models.StepModel{}, fmt.Errorf("no step version found for (ID: %s) (version: %s)", stepID, stepGroup.LatestVersionNumber)
+	}
+	return prepareStep(step, stepID, stepGroup.LatestVersionNumber)
+}
+
+// ReadStepLibIndex returns the index of the collection. The index is read from the index cache,
+// if it was built from the current spec.json, otherwise it's built and the cache is updated.
+// The spec.json is the same if its generated_at_timestamp, modification time and size are the same:
+// the timestamp changes with every spec generation, the file info catches specs written by other tools.
+// The index of a local StepLib is always built from the StepLib's directory.
+// Failing to write the cache is not an error, the index is built again the next time.
+func ReadStepLibIndex(collectionID string) (StepLibIndexModel, error) {
+	stepLib, err := readSetUpStepLib(collectionID)
+	if err != nil {
+		return StepLibIndexModel{}, err
+	}
+
+	// local StepLibs change frequently and have no spec.json, these are not cached
+	if stepLib.IsLocal {
+		spec, err := stepLib.ReadSpec()
+		if err != nil {
+			return StepLibIndexModel{}, err
+		}
+		return NewStepLibIndex(stepLib.URI, spec), nil
+	}
+
+	specInfo, err := os.Stat(stepLib.SpecPath())
+	if err != nil {
+		return StepLibIndexModel{}, fmt.Errorf("failed to read spec json: %s", err)
+	}
+
+	specGeneratedAt, err := readSpecGeneratedAt(stepLib.SpecPath())
+	if err != nil {
+		return StepLibIndexModel{}, err
+	}
+
+	indexPth, err := indexPath(stepLib)
+	if err != nil {
+		return StepLibIndexModel{}, err
+	}
+
+	if index, ok := readIndex(indexPth); ok &&
+		index.FormatVersion == indexFormatVersion &&
+		index.URI == stepLib.URI &&
+		index.SpecPath == stepLib.SpecPath() &&
+		index.SpecGeneratedAt == specGeneratedAt &&
+		index.SpecModTime.Equal(specInfo.ModTime()) &&
+		index.SpecSize == specInfo.Size() {
+		return index, nil
+	}
+
+	spec, err := stepLib.ReadSpec()
+	if err != nil {
+		return StepLibIndexModel{}, err
+	}
+
+	index := NewStepLibIndex(stepLib.URI, spec)
+	index.SpecPath = stepLib.SpecPath()
+	index.SpecGeneratedAt = spec.GeneratedAtTimeStamp
+	index.SpecModTime = specInfo.ModTime()
+	index.SpecSize = specInfo.Size()
+
+	_ = writeIndex(indexPth, index)
+	return index, nil
+}
+
+// IndexCacheDir returns the directory of the StepLib indexes: the value of the IndexCacheDirEnvKey
+// environment variable, or bitrise-plugins-step/steplib-index in the user's cache directory.
+func IndexCacheDir() (string, error) {
+	if dir := os.Getenv(IndexCacheDirEnvKey); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache directory: %s", err)
+	}
+	return filepath.Join(cacheDir, "bitrise-plugins-step", "steplib-index"), nil
+}
+
+// indexPath returns the path of the StepLib's index, which is unique for each route
+// (the StepLib's cache directory includes the stepman home and the route's folder alias).
+func indexPath(stepLib StepLibModel) (string, error) {
+	dir, err := IndexCacheDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(stepLib.URI + "\n" + stepLib.Dir))
+	return filepath.Join(dir, hex.EncodeToString(hash[:16])+".json"), nil
+}
+
+// readSpecGeneratedAt reads the generated_at_timestamp of the spec.json, without parsing the steps:
+// stepman writes it before the steps, so the rest of the file is not read. It's 0 if the spec has no timestamp.
+func readSpecGeneratedAt(pth string) (int64, error) {
+	file, err := os.Open(pth)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read spec json: %s", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := json.NewDecoder(file)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return 0, fmt.Errorf("failed to parse spec json (%s): not a JSON object", pth)
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse spec json (%s): %s", pth, err)
+		}
+		if key == "generated_at_timestamp" {
+			var timestamp int64
+			if err := decoder.Decode(&timestamp); err != nil {
+				return 0, fmt.Errorf("failed to parse generated_at_timestamp of spec json (%s): %s", pth, err)
+			}
+			return timestamp, nil
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return 0, fmt.Errorf("failed to parse spec json (%s): %s", pth, err)
+		}
+	}
+	return 0, nil
+}
+
+func readIndex(pth string) (StepLibIndexModel, bool) {
+	bytes, err := os.ReadFile(pth)
+	if err != nil {
+		return StepLibIndexModel{}, false
+	}
+	var index StepLibIndexModel
+	if err := json.Unmarshal(bytes, &index); err != nil {
+		return StepLibIndexModel{}, false
+	}
+	return index, true
+}
+
+// writeIndex writes the index through a temporary file, so that concurrent readers
+// never see a partially written index.
+func writeIndex(pth string, index StepLibIndexModel) error {
+	bytes, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(pth), filepath.Base(pth)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(bytes); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), pth)
+}
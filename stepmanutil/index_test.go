@@ -0,0 +1,120 @@
+package stepmanutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStepLibIndex(t *testing.T) {
+	homeDir := createStepmanHome(t)
+	t.Setenv(StepmanHomeEnvKey, homeDir)
+	t.Setenv(IndexCacheDirEnvKey, t.TempDir())
+
+	t.Log("index is built from the spec.json")
+	index, err := ReadStepLibIndex(DefaultCollectionURI)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.1.0", "1.0.0"}, index.Versions["script"])
+	require.Equal(t, 1, len(index.Collection.Steps["script"].Versions))
+	require.Equal(t, int64(1700000000), index.Collection.GeneratedAtTimeStamp)
+
+	step, err := index.LatestStepVersion("script")
+	require.NoError(t, err)
+	require.Equal(t, "Script", pointers.String(step.Title))
+
+	resolution, err := index.ResolveStepVersion("script", "1.0")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", resolution.Version)
+
+	stepLib, found, err := ReadStepLib(DefaultCollectionURI)
+	require.NoError(t, err)
+	require.True(t, found)
+	indexPth, err := indexPath(stepLib)
+	require.NoError(t, err)
+
+	t.Log("cached index is used while the spec.json does not change")
+	{
+		cached := index
+		cached.Collection.SteplibSource = "cached"
+		require.NoError(t, writeIndex(indexPth, cached))
+
+		index, err := ReadStepLibIndex(DefaultCollectionURI)
+		require.NoError(t, err)
+		require.Equal(t, "cached", index.Collection.SteplibSource)
+	}
+
+	t.Log("index is rebuilt if the spec.json is regenerated, even with the same size and modification time")
+	{
+		specPth := stepLib.SpecPath()
+		specInfo, err := os.Stat(specPth)
+		require.NoError(t, err)
+		content, err := os.ReadFile(specPth)
+		require.NoError(t, err)
+		content = bytes.Replace(content, []byte("1700000000"), []byte("1700000001"), 1)
+		require.NoError(t, os.WriteFile(specPth, content, 0600))
+		require.NoError(t, os.Chtimes(specPth, specInfo.ModTime(), specInfo.ModTime()))
+
+		index, err := ReadStepLibIndex(DefaultCollectionURI)
+		require.NoError(t, err)
+		require.NotEqual(t, "cached", index.Collection.SteplibSource)
+		require.Equal(t, int64(1700000001), index.SpecGeneratedAt)
+	}
+
+	t.Log("index is rebuilt if the spec.json changes")
+	{
+		specPth := stepLib.SpecPath()
+		require.NoError(t, os.WriteFile(specPth, []byte(`{"steps": {"git-clone": {"latest_version_number": "8.0.0", "versions": {"8.0.0": {}}}}}`), 0600))
+
+		index, err := ReadStepLibIndex(DefaultCollectionURI)
+		require.NoError(t, err)
+		require.Equal(t, []string{"8.0.0"}, index.Versions["git-clone"])
+		_, found := index.Collection.Steps["script"]
+		require.False(t, found)
+	}
+
+	t.Log("index cache is optional")
+	{
+		t.Setenv(IndexCacheDirEnvKey, filepath.Join(homeDir, "routing.json", "not-a-dir"))
+		index, err := ReadStepLibIndex(DefaultCollectionURI)
+		require.NoError(t, err)
+		require.Equal(t, "8.0.0", index.Collection.Steps["git-clone"].LatestVersionNumber)
+	}
+}
+
+func TestReadStepVersionInfo(t *testing.T) {
+	t.Setenv(StepmanHomeEnvKey, createStepmanHome(t))
+	t.Setenv(IndexCacheDirEnvKey, t.TempDir())
+
+	t.Log("latest version")
+	{
+		info, version, err := ReadStepVersionInfo(DefaultCollectionURI, "script", "")
+		require.NoError(t, err)
+		require.Equal(t, "1.1.0", version)
+		require.Equal(t, StepVersionModel{
+			Title:       "Script",
+			Description: "Runs a script",
+			Inputs: []StepInputModel{
+				{Key: "content", Description: "Script content", DefaultValue: "echo hello", IsExpand: false},
+				{Key: "runner_bin", DefaultValue: "/bin/bash", ValueOptions: []string{"/bin/bash", "/bin/zsh"}, IsExpand: true},
+			},
+		}, info)
+	}
+
+	t.Log("earlier version")
+	{
+		info, version, err := ReadStepVersionInfo(DefaultCollectionURI, "script", "1.0")
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", version)
+		require.Equal(t, "Script", info.Title)
+	}
+
+	t.Log("not existing version")
+	{
+		_, _, err := ReadStepVersionInfo(DefaultCollectionURI, "script", "2")
+		require.Error(t, err)
+	}
+}
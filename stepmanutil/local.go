@@ -0,0 +1,124 @@
+package stepmanutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+)
+
+const fileURIPrefix = "file://"
+
+// LocalCollectionDir returns the directory of a local StepLib, found is false if the collection is not local.
+// A collection is local if it's a file:// URI, or a path of an existing directory.
+func LocalCollectionDir(collectionID string) (string, bool) {
+	pth := collectionID
+	if strings.HasPrefix(collectionID, fileURIPrefix) {
+		pth = strings.TrimPrefix(collectionID, fileURIPrefix)
+	} else if strings.Contains(collectionID, "://") || strings.HasPrefix(collectionID, "git@") {
+		return "", false
+	}
+	if pth == "" {
+		return "", false
+	}
+
+	absPth, err := pathutil.AbsPath(pth)
+	if err != nil {
+		return "", false
+	}
+	if strings.HasPrefix(collectionID, fileURIPrefix) {
+		return absPth, true
+	}
+	if exist, err := pathutil.IsDirExists(absPth); err != nil || !exist {
+		return "", false
+	}
+	return absPth, true
+}
+
+// CollectionURI returns the URI of the collection, which can be used as a StepLib source in a bitrise.yml:
+// local StepLib directories are converted to a file:// URI with an absolute path, other collections are returned as they are.
+func CollectionURI(collectionID string) string {
+	if dir, isLocal := LocalCollectionDir(collectionID); isLocal {
+		return fileURIPrefix + dir
+	}
+	return collectionID
+}
+
+// ReadLocalCollection parses a StepLib directory, the same way as stepman generates the spec.json of a StepLib:
+// every steps/<id>/<version>/step.yml is a step version, steps/<id>/step-info.yml is the step's group info,
+// and the steplib.yml in the root of the directory (optional) defines the StepLib's properties.
+// The step.yml files are not validated, to be able to inspect the steps under development.
+func ReadLocalCollection(dir string) (models.StepCollectionModel, error) {
+	var collection models.StepCollectionModel
+	steplibYMLPth := filepath.Join(dir, "steplib.yml")
+	if exist, err := pathutil.IsPathExists(steplibYMLPth); err != nil {
+		return models.StepCollectionModel{}, err
+	} else if exist {
+		if collection, err = stepman.ParseStepCollection(steplibYMLPth); err != nil {
+			return models.StepCollectionModel{}, fmt.Errorf("failed to parse steplib.yml: %s", err)
+		}
+	}
+	collection.GeneratedAtTimeStamp = time.Now().Unix()
+	collection.Steps = models.StepHash{}
+
+	stepsDir := filepath.Join(dir, "steps")
+	stepEntries, err := os.ReadDir(stepsDir)
+	if err != nil {
+		return models.StepCollectionModel{}, fmt.Errorf("failed to read steps directory: %s", err)
+	}
+
+	for _, stepEntry := range stepEntries {
+		if !stepEntry.IsDir() {
+			continue
+		}
+		stepID := stepEntry.Name()
+
+		versionEntries, err := os.ReadDir(filepath.Join(stepsDir, stepID))
+		if err != nil {
+			return models.StepCollectionModel{}, fmt.Errorf("failed to read step (%s) directory: %s", stepID, err)
+		}
+
+		stepGroup := models.StepGroupModel{Versions: map[string]models.StepModel{}}
+		var versions []string
+		for _, versionEntry := range versionEntries {
+			if !versionEntry.IsDir() {
+				continue
+			}
+			version := versionEntry.Name()
+
+			stepYMLPth := filepath.Join(stepsDir, stepID, version, "step.yml")
+			if exist, err := pathutil.IsPathExists(stepYMLPth); err != nil {
+				return models.StepCollectionModel{}, err
+			} else if !exist {
+				continue
+			}
+
+			step, err := stepman.ParseStepDefinition(stepYMLPth, false)
+			if err != nil {
+				return models.StepCollectionModel{}, fmt.Errorf("failed to parse step (%s@%s): %s", stepID, version, err)
+			}
+			stepGroup.Versions[version] = step
+			versions = append(versions, version)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		SortVersions(versions)
+		stepGroup.LatestVersionNumber = versions[0]
+
+		info, found, err := stepman.ParseStepGroupInfoModel(filepath.Join(stepsDir, stepID, "step-info.yml"))
+		if err != nil {
+			return models.StepCollectionModel{}, fmt.Errorf("failed to parse step (%s) info: %s", stepID, err)
+		} else if found {
+			stepGroup.Info = info
+		}
+
+		collection.Steps[stepID] = stepGroup
+	}
+	return collection, nil
+}
@@ -0,0 +1,110 @@
+package stepmanutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCollectionDir(t *testing.T) {
+	absDir, err := filepath.Abs("./testdata/steplib")
+	require.NoError(t, err)
+
+	t.Log("file URI")
+	{
+		dir, isLocal := LocalCollectionDir("file://" + absDir)
+		require.True(t, isLocal)
+		require.Equal(t, absDir, dir)
+
+		dir, isLocal = LocalCollectionDir("file:///not/existing/steplib")
+		require.True(t, isLocal)
+		require.Equal(t, "/not/existing/steplib", dir)
+	}
+
+	t.Log("existing directory")
+	{
+		dir, isLocal := LocalCollectionDir("./testdata/steplib")
+		require.True(t, isLocal)
+		require.Equal(t, absDir, dir)
+	}
+
+	t.Log("not local")
+	{
+		for _, collectionID := range []string{
+			DefaultCollectionURI,
+			"git@github.com:my-org/my-steplib.git",
+			"./testdata/not-existing",
+			"",
+		} {
+			_, isLocal := LocalCollectionDir(collectionID)
+			require.False(t, isLocal, collectionID)
+		}
+	}
+}
+
+func TestCollectionURI(t *testing.T) {
+	absDir, err := filepath.Abs("./testdata/steplib")
+	require.NoError(t, err)
+
+	require.Equal(t, "file://"+absDir, CollectionURI("./testdata/steplib"))
+	require.Equal(t, "file://"+absDir, CollectionURI("file://"+absDir))
+	require.Equal(t, DefaultCollectionURI, CollectionURI(DefaultCollectionURI))
+}
+
+func TestReadLocalCollection(t *testing.T) {
+	collection, err := ReadLocalCollection("./testdata/steplib")
+	require.NoError(t, err)
+
+	require.Equal(t, "https://github.com/my-org/my-steplib.git", collection.SteplibSource)
+	require.Equal(t, 2, len(collection.Steps))
+
+	script := collection.Steps["script"]
+	require.Equal(t, "1.1.0", script.LatestVersionNumber)
+	require.Equal(t, 2, len(script.Versions))
+	require.Equal(t, "community", script.Info.Maintainer)
+	require.Equal(t, "Run a script, with a custom runner", pointers.String(script.Versions["1.1.0"].Summary))
+	require.Equal(t, 2, len(script.Versions["1.1.0"].Inputs))
+
+	deploy := collection.Steps["my-deploy"]
+	require.Equal(t, "0.1.0", deploy.LatestVersionNumber)
+	require.Equal(t, []string{"deploy"}, deploy.Versions["0.1.0"].TypeTags)
+
+	t.Log("not a StepLib directory")
+	{
+		_, err := ReadLocalCollection("./testdata/steplib/steps")
+		require.Error(t, err)
+	}
+}
+
+func TestLocalStepLib(t *testing.T) {
+	t.Setenv(IndexCacheDirEnvKey, t.TempDir())
+	collectionID := "./testdata/steplib"
+
+	require.NoError(t, EnsureCollectionIsSetUp(collectionID))
+	require.EqualError(t, EnsureCollectionIsSetUp("file:///not/existing/steplib"), "local collection (file:///not/existing/steplib) directory does not exist: /not/existing/steplib")
+
+	stepLib, found, err := ReadStepLib(collectionID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, stepLib.IsLocal)
+	require.Equal(t, "", stepLib.SpecPath())
+	absDir, err := filepath.Abs(collectionID)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(absDir, "steps", "script", "1.0.0", "step.yml"), stepLib.StepDefinitionPath("script", "1.0.0"))
+
+	index, err := ReadStepLibIndex(collectionID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.1.0", "1.0.0"}, index.Versions["script"])
+
+	collection, err := ReadStepCollectionModel(collectionID)
+	require.NoError(t, err)
+	step, resolution, err := StepVersion(collection, "script", "1.0")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", resolution.Version)
+	require.Equal(t, 1, len(step.Inputs))
+
+	_, err = UpdateCollection(collectionID)
+	require.Error(t, err)
+}
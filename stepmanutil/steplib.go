@@ -0,0 +1,184 @@
+package stepmanutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+)
+
+// StepmanHomeEnvKey is the environment variable which can be used
+// to read the StepLibs from a stepman home other than ~/.stepman
+const StepmanHomeEnvKey = "STEPMAN_HOME"
+
+// StepLibModel is a StepLib (stepman collection) cached locally by stepman,
+// or a local StepLib directory (see LocalCollectionDir).
+// Reading a StepLib only reads the local cache, it works offline.
+type StepLibModel struct {
+	URI string
+	// Dir is the cache directory of the StepLib: <stepman home>/step_collections/<alias>,
+	// or the directory of a local StepLib
+	Dir string
+	// LibraryDir is the directory of the StepLib's content, which has the steps directory
+	LibraryDir string
+	// IsLocal is true for local StepLibs, which are not set up with stepman and have no spec.json
+	IsLocal bool
+}
+
+// StepmanHomeDir returns the stepman home directory: the value of the StepmanHomeEnvKey
+// environment variable, or ~/.stepman.
+func StepmanHomeDir() string {
+	if homeDir := os.Getenv(StepmanHomeEnvKey); homeDir != "" {
+		return homeDir
+	}
+	return stepman.GetStepmanDirPath()
+}
+
+// IsCustomStepmanHome returns true if the stepman home is overridden by the StepmanHomeEnvKey
+// environment variable. stepman itself only manages the StepLibs of ~/.stepman.
+func IsCustomStepmanHome() bool {
+	return os.Getenv(StepmanHomeEnvKey) != ""
+}
+
+// readRoutes returns the folder alias of the StepLibs by URI, from the routing file of the stepman home.
+func readRoutes() (map[string]string, error) {
+	pth := filepath.Join(StepmanHomeDir(), stepman.RoutingFilename)
+	bytes, err := os.ReadFile(pth)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read routing file (%s): %s", pth, err)
+	}
+
+	var routes map[string]string
+	if err := json.Unmarshal(bytes, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse routing file (%s): %s", pth, err)
+	}
+	return routes, nil
+}
+
+// ReadStepLib returns the StepLib with the given URI, found is false
+// if the StepLib is not set up in the stepman home, or the local StepLib's directory does not exist.
+func ReadStepLib(uri string) (StepLibModel, bool, error) {
+	if dir, isLocal := LocalCollectionDir(uri); isLocal {
+		if exist, err := pathutil.IsDirExists(dir); err != nil {
+			return StepLibModel{}, false, err
+		} else if !exist {
+			return StepLibModel{}, false, nil
+		}
+		return StepLibModel{URI: uri, Dir: dir, LibraryDir: dir, IsLocal: true}, true, nil
+	}
+
+	routes, err := readRoutes()
+	if err != nil {
+		return StepLibModel{}, false, err
+	}
+
+	alias, found := routes[uri]
+	if !found {
+		return StepLibModel{}, false, nil
+	}
+
+	dir := filepath.Join(StepmanHomeDir(), stepman.CollectionsDirname, alias)
+	stepLib := StepLibModel{
+		URI:        uri,
+		Dir:        dir,
+		LibraryDir: filepath.Join(dir, "collection"),
+	}
+	if exist, err := pathutil.IsDirExists(stepLib.Dir); err != nil {
+		return StepLibModel{}, false, err
+	} else if !exist {
+		return StepLibModel{}, false, nil
+	}
+	return stepLib, true, nil
+}
+
+// CollectionIDs returns the URI of every StepLib which is set up in the stepman home, in alphabetical order.
+func CollectionIDs() ([]string, error) {
+	routes, err := readRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(routes))
+	for uri := range routes {
+		ids = append(ids, uri)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// SpecPath returns the path of the spec.json, generated from the StepLib.
+// Local StepLibs have no spec.json, the path is empty for them.
+func (stepLib StepLibModel) SpecPath() string {
+	if stepLib.IsLocal {
+		return ""
+	}
+	return filepath.Join(stepLib.Dir, "spec", "spec.json")
+}
+
+// StepDefinitionPath returns the path of the step.yml of the step version.
+func (stepLib StepLibModel) StepDefinitionPath(stepID, version string) string {
+	return filepath.Join(stepLib.LibraryDir, "steps", stepID, version, "step.yml")
+}
+
+// StepGroupInfoPath returns the path of the step-info.yml of the step.
+func (stepLib StepLibModel) StepGroupInfoPath(stepID string) string {
+	return filepath.Join(stepLib.LibraryDir, "steps", stepID, "step-info.yml")
+}
+
+// ReadSpec reads the spec.json of the StepLib, local StepLibs are parsed from their directory.
+func (stepLib StepLibModel) ReadSpec() (models.StepCollectionModel, error) {
+	if stepLib.IsLocal {
+		return ReadLocalCollection(stepLib.LibraryDir)
+	}
+
+	file, err := os.Open(stepLib.SpecPath())
+	if err != nil {
+		return models.StepCollectionModel{}, fmt.Errorf("failed to open spec json: %s", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Warnf("Failed to close spec json: %s", err)
+		}
+	}()
+
+	var spec models.StepCollectionModel
+	if err := json.NewDecoder(file).Decode(&spec); err != nil {
+		return models.StepCollectionModel{}, fmt.Errorf("failed to parse spec json: %s", err)
+	}
+	return spec, nil
+}
+
+// SpecGeneratedAt returns the time the spec.json was generated at,
+// which is the last time the StepLib was updated. found is false if the spec has no generation time.
+func SpecGeneratedAt(spec models.StepCollectionModel) (time.Time, bool) {
+	if spec.GeneratedAtTimeStamp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(spec.GeneratedAtTimeStamp, 0), true
+}
+
+// UpdateCollection updates the StepLib cache with stepman (git pull and spec regeneration).
+// Updating requires network access, and it's only supported in the default stepman home.
+// Local StepLibs are always read from their directory, these can not be updated.
+func UpdateCollection(collectionID string) (models.StepCollectionModel, error) {
+	if _, isLocal := LocalCollectionDir(collectionID); isLocal {
+		return models.StepCollectionModel{}, fmt.Errorf("local collection (%s) is read from its directory, it does not need to be updated", collectionID)
+	}
+	if IsCustomStepmanHome() {
+		return models.StepCollectionModel{}, fmt.Errorf("updating a collection is not supported with %s (%s), stepman only updates the collections of %s", StepmanHomeEnvKey, StepmanHomeDir(), stepman.GetStepmanDirPath())
+	}
+	spec, err := stepman.UpdateLibrary(collectionID, log.NewDefaultLogger(false))
+	if err != nil {
+		return models.StepCollectionModel{}, fmt.Errorf("failed to update collection (%s): %s", collectionID, err)
+	}
+	return spec, nil
+}
@@ -0,0 +1,104 @@
+package stepmanutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func createStepmanHome(t *testing.T) string {
+	homeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(homeDir, "routing.json"), []byte(`{
+  "https://github.com/bitrise-io/bitrise-steplib.git": "1600000000",
+  "https://github.com/my-org/my-steplib.git": "1600000001"
+}`), 0600))
+
+	specDir := filepath.Join(homeDir, "step_collections", "1600000000", "spec")
+	require.NoError(t, os.MkdirAll(specDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.json"), []byte(`{
+  "format_version": "1.0.0",
+  "generated_at_timestamp": 1700000000,
+  "steplib_source": "https://github.com/bitrise-io/bitrise-steplib.git",
+  "steps": {
+    "script": {
+      "latest_version_number": "1.1.0",
+      "versions": {
+        "1.0.0": {"title": "Script"},
+        "1.1.0": {
+          "title": "Script",
+          "description": "Runs a script",
+          "inputs": [
+            {"content": "echo hello", "opts": {"description": "Script content", "is_expand": false}},
+            {"runner_bin": "/bin/bash", "opts": {"value_options": ["/bin/bash", "/bin/zsh"]}}
+          ]
+        }
+      }
+    }
+  }
+}`), 0600))
+	return homeDir
+}
+
+func TestReadStepLib(t *testing.T) {
+	homeDir := createStepmanHome(t)
+	t.Setenv(StepmanHomeEnvKey, homeDir)
+	require.True(t, IsCustomStepmanHome())
+	require.Equal(t, homeDir, StepmanHomeDir())
+
+	t.Log("collections of the custom stepman home")
+	{
+		ids, err := CollectionIDs()
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://github.com/bitrise-io/bitrise-steplib.git", "https://github.com/my-org/my-steplib.git"}, ids)
+	}
+
+	t.Log("set up collection")
+	{
+		stepLib, found, err := ReadStepLib(DefaultCollectionURI)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, filepath.Join(homeDir, "step_collections", "1600000000", "spec", "spec.json"), stepLib.SpecPath())
+		require.Equal(t, filepath.Join(homeDir, "step_collections", "1600000000", "collection", "steps", "script", "1.1.0", "step.yml"), stepLib.StepDefinitionPath("script", "1.1.0"))
+
+		spec, err := stepLib.ReadSpec()
+		require.NoError(t, err)
+		require.Equal(t, "1.1.0", spec.Steps["script"].LatestVersionNumber)
+
+		generatedAt, found := SpecGeneratedAt(spec)
+		require.True(t, found)
+		require.Equal(t, time.Unix(1700000000, 0), generatedAt)
+
+		require.NoError(t, EnsureCollectionIsSetUp(DefaultCollectionURI))
+	}
+
+	t.Log("routed collection without cache directory is not set up")
+	{
+		_, found, err := ReadStepLib("https://github.com/my-org/my-steplib.git")
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Error(t, EnsureCollectionIsSetUp("https://github.com/my-org/my-steplib.git"))
+	}
+
+	t.Log("update is not supported in a custom stepman home")
+	{
+		_, err := UpdateCollection(DefaultCollectionURI)
+		require.Error(t, err)
+	}
+
+	t.Log("no generation time")
+	{
+		_, found := SpecGeneratedAt(models.StepCollectionModel{})
+		require.False(t, found)
+	}
+}
+
+func TestCollectionIDsWithoutRoutes(t *testing.T) {
+	t.Setenv(StepmanHomeEnvKey, t.TempDir())
+	ids, err := CollectionIDs()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(ids))
+}
@@ -1,20 +1,57 @@
 package stepmanutil
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
-	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/pathutil"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
 	"github.com/bitrise-io/stepman/models"
 )
 
 const (
-	// hardcoded, while stepman does not have this feature
-	stepmanRoutesPath = "~/.stepman/routing.json"
+	// DefaultCollectionURI is the URI of the official Bitrise StepLib
+	DefaultCollectionURI = "https://github.com/bitrise-io/bitrise-steplib.git"
+	// CollectionEnvKey is the environment variable which can be used
+	// to override the default collection
+	CollectionEnvKey = "BITRISE_STEP_COLLECTION"
 )
 
+// CollectionOrDefault returns the given collection, or if it's empty the default one:
+// the value of the CollectionEnvKey environment variable, or the official Bitrise StepLib.
+func CollectionOrDefault(collectionID string) string {
+	if collectionID != "" {
+		return collectionID
+	}
+	if collectionID := os.Getenv(CollectionEnvKey); collectionID != "" {
+		return collectionID
+	}
+	return DefaultCollectionURI
+}
+
+// EnsureCollectionIsSetUp returns an error with a hint about setting up the collection,
+// if the collection is not set up (there is no stepman route for it),
+// or if it's a local collection, whose directory does not exist.
+func EnsureCollectionIsSetUp(collectionID string) error {
+	_, err := readSetUpStepLib(collectionID)
+	return err
+}
+
+// readSetUpStepLib returns the StepLib, or an error with a hint about setting it up, if it's not set up.
+func readSetUpStepLib(collectionID string) (StepLibModel, error) {
+	stepLib, found, err := ReadStepLib(collectionID)
+	if err != nil {
+		return StepLibModel{}, err
+	}
+	if !found {
+		if dir, isLocal := LocalCollectionDir(collectionID); isLocal {
+			return StepLibModel{}, fmt.Errorf("local collection (%s) directory does not exist: %s", collectionID, dir)
+		}
+		return StepLibModel{}, fmt.Errorf("collection (%s) is not set up, you can set it up with: stepman setup --collection %s", collectionID, collectionID)
+	}
+	return stepLib, nil
+}
+
 // StepInputModel ...
 type StepInputModel struct {
 	Key          string   `json:"key"`
@@ -42,83 +79,90 @@ type SpecJSONModel struct {
 	Steps map[string]StepInfoModel `json:"steps"`
 }
 
-// ReadStepCollectionModel ...
+// ReadStepCollectionModel reads the spec.json of the collection, from the local StepLib cache.
 func ReadStepCollectionModel(collectionID string) (models.StepCollectionModel, error) {
-	specJSONPath, err := specJSONPathOfCollection(collectionID)
-	if err != nil {
-		return models.StepCollectionModel{}, fmt.Errorf("failed to get spec json path: %s", err)
-	}
-
-	file, err := os.Open(specJSONPath)
+	stepLib, err := readSetUpStepLib(collectionID)
 	if err != nil {
-		return models.StepCollectionModel{}, fmt.Errorf("failed to open spec json: %s", err)
-	}
-	var spec models.StepCollectionModel
-	if err := json.NewDecoder(file).Decode(&spec); err != nil {
-		return models.StepCollectionModel{}, fmt.Errorf("failed to parse spec json: %s", err)
+		return models.StepCollectionModel{}, err
 	}
-	return spec, nil
+	return stepLib.ReadSpec()
 }
 
 // ReadStepVersionInfo ...
 // If `stepVersion` is empty, the function will return with the latest
-// available version of the step.
+// available version of the step. `stepVersion` can also be a major or minor
+// locked version constraint (e.g. 8 or 8.1.x), see ResolveVersion.
 func ReadStepVersionInfo(collectionID, stepID, stepVersion string) (StepVersionModel, string, error) {
-	specJSONPath, err := specJSONPathOfCollection(collectionID)
+	index, err := ReadStepLibIndex(collectionID)
 	if err != nil {
-		return StepVersionModel{}, "", fmt.Errorf("failed to get spec json path: %s", err)
+		return StepVersionModel{}, "", err
 	}
 
-	file, err := os.Open(specJSONPath)
+	resolution, err := index.ResolveStepVersion(stepID, stepVersion)
 	if err != nil {
-		return StepVersionModel{}, "", fmt.Errorf("failed to open spec json: %s", err)
-	}
-	var spec SpecJSONModel
-	if err := json.NewDecoder(file).Decode(&spec); err != nil {
-		return StepVersionModel{}, "", fmt.Errorf("failed to parse spec json: %s", err)
+		return StepVersionModel{}, "", err
 	}
 
-	stepInfo, isFound := spec.Steps[stepID]
-	if !isFound {
-		return StepVersionModel{}, "", fmt.Errorf("no step found for ID: %s", stepID)
+	// the index only contains the latest version of the steps
+	collection := index.Collection
+	if resolution.Version != collection.Steps[stepID].LatestVersionNumber {
+		if collection, err = ReadStepCollectionModel(collectionID); err != nil {
+			return StepVersionModel{}, "", err
+		}
 	}
-
-	if stepVersion == "" {
-		stepVersion = stepInfo.LatestVersion
+	step, found := collection.Steps[stepID].Versions[resolution.Version]
+	if !found {
+		return StepVersionModel{}, "", fmt.Errorf("no step version found for (ID: %s) (version: %s)", stepID, resolution.Version)
 	}
 
-	stepVersionInfo, isFound := stepInfo.StepVersions[stepVersion]
-	if !isFound {
-		return StepVersionModel{}, "", fmt.Errorf("no step version found for (ID: %s) (version: %s)", stepID, stepVersion)
+	stepVersionInfo, err := newStepVersionModel(step)
+	if err != nil {
+		return StepVersionModel{}, "", fmt.Errorf("failed to read step (%s@%s): %s", stepID, resolution.Version, err)
 	}
-
-	return stepVersionInfo, stepVersion, nil
+	return stepVersionInfo, resolution.Version, nil
 }
 
-func specJSONPathOfCollection(collectionID string) (string, error) {
-	routesAbsPath, err := pathutil.AbsPath(stepmanRoutesPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path for stepman routing file: %s", err)
+func newStepVersionModel(step models.StepModel) (StepVersionModel, error) {
+	stepVersionInfo := StepVersionModel{
+		Title:       pointers.String(step.Title),
+		Description: pointers.String(step.Description),
 	}
-	bytes, err := fileutil.ReadBytesFromFile(routesAbsPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read content of routing file: %s", err)
-	}
-	var routeMap map[string]string
-	if err := json.Unmarshal(bytes, &routeMap); err != nil {
-		return "", fmt.Errorf("failed to parse content of routing file: %s", err)
+	for _, input := range step.Inputs {
+		key, value, err := input.GetKeyValuePair()
+		if err != nil {
+			return StepVersionModel{}, err
+		}
+		options, err := input.GetOptions()
+		if err != nil {
+			return StepVersionModel{}, err
+		}
+		stepVersionInfo.Inputs = append(stepVersionInfo.Inputs, StepInputModel{
+			Key:          key,
+			Description:  pointers.String(options.Description),
+			DefaultValue: value,
+			ValueOptions: options.ValueOptions,
+			IsExpand:     pointers.BoolWithDefault(options.IsExpand, envmanModels.DefaultIsExpand),
+		})
 	}
+	return stepVersionInfo, nil
+}
 
-	val, isFound := routeMap[collectionID]
-	if !isFound {
-		return "", fmt.Errorf("specified collection (%s) not found in routing", collectionID)
+// ToolkitName returns the name of the step's toolkit (bash, go, swift or kotlin),
+// or an empty string if the step does not define a toolkit.
+// Steps without toolkit are run with the bash toolkit.
+func ToolkitName(toolkit *models.StepToolkitModel) string {
+	if toolkit == nil {
+		return ""
 	}
-
-	specPath := fmt.Sprintf("~/.stepman/step_collections/%s/spec/spec.json", val)
-	absSpecJSONPath, err := pathutil.AbsPath(specPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path of spec.json")
+	switch {
+	case toolkit.Bash != nil:
+		return "bash"
+	case toolkit.Go != nil:
+		return "go"
+	case toolkit.Swift != nil:
+		return "swift"
+	case toolkit.Kotlin != nil:
+		return "kotlin"
 	}
-
-	return absSpecJSONPath, nil
+	return ""
 }
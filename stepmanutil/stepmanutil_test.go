@@ -0,0 +1,88 @@
+package stepmanutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionOrDefault(t *testing.T) {
+	t.Setenv(CollectionEnvKey, "")
+	require.Equal(t, "https://my.steplib.git", CollectionOrDefault("https://my.steplib.git"))
+	require.Equal(t, DefaultCollectionURI, CollectionOrDefault(""))
+
+	t.Setenv(CollectionEnvKey, "https://env.steplib.git")
+	require.Equal(t, "https://env.steplib.git", CollectionOrDefault(""))
+	require.Equal(t, "https://my.steplib.git", CollectionOrDefault("https://my.steplib.git"))
+}
+
+func TestEnsureCollectionIsSetUp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(StepmanHomeEnvKey, "")
+	err := EnsureCollectionIsSetUp("https://my.steplib.git")
+	require.EqualError(t, err, "collection (https://my.steplib.git) is not set up, you can set it up with: stepman setup --collection https://my.steplib.git")
+}
+
+func TestResolveVersion(t *testing.T) {
+	versions := []string{"7.0.0", "8.0.0", "8.1.0", "8.1.2", "8.10.0", "8.2.1", "1.0.0-beta"}
+
+	t.Log("latest")
+	{
+		resolution, err := ResolveVersion(versions, "8.10.0", "")
+		require.NoError(t, err)
+		require.Equal(t, "8.10.0", resolution.Version)
+		require.False(t, resolution.IsConstraint())
+	}
+
+	t.Log("exact version")
+	{
+		resolution, err := ResolveVersion(versions, "8.10.0", "8.1.0")
+		require.NoError(t, err)
+		require.Equal(t, "8.1.0", resolution.Version)
+		require.Equal(t, []string{"8.1.0"}, resolution.Candidates)
+		require.False(t, resolution.IsConstraint())
+	}
+
+	t.Log("exact non semver version")
+	{
+		resolution, err := ResolveVersion(versions, "8.10.0", "1.0.0-beta")
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0-beta", resolution.Version)
+	}
+
+	t.Log("major locked")
+	{
+		for _, constraint := range []string{"8", "8.x.x"} {
+			resolution, err := ResolveVersion(versions, "8.10.0", constraint)
+			require.NoError(t, err)
+			require.Equal(t, "8.10.0", resolution.Version)
+			require.Equal(t, []string{"8.10.0", "8.2.1", "8.1.2", "8.1.0", "8.0.0"}, resolution.Candidates)
+			require.True(t, resolution.IsConstraint())
+		}
+	}
+
+	t.Log("minor locked")
+	{
+		for _, constraint := range []string{"8.1", "8.1.x"} {
+			resolution, err := ResolveVersion(versions, "8.10.0", constraint)
+			require.NoError(t, err)
+			require.Equal(t, "8.1.2", resolution.Version)
+			require.Equal(t, []string{"8.1.2", "8.1.0"}, resolution.Candidates)
+		}
+	}
+
+	t.Log("no matching version")
+	{
+		_, err := ResolveVersion(versions, "8.10.0", "9")
+		require.EqualError(t, err, "no version found matching: 9")
+
+		_, err = ResolveVersion(versions, "8.10.0", "8.1.5")
+		require.EqualError(t, err, "no version found matching: 8.1.5")
+	}
+
+	t.Log("invalid constraint")
+	{
+		_, err := ResolveVersion(versions, "8.10.0", "latest")
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,224 @@
+package stepmanutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/stepman/models"
+)
+
+// VersionResolutionModel is the result of resolving a version constraint
+// (e.g. 8, 8.1 or 8.1.x) against the available versions of a step.
+type VersionResolutionModel struct {
+	Constraint string
+	// Version is the concrete version the constraint resolved to
+	Version string
+	// Candidates are every available version matching the constraint, highest first
+	Candidates []string
+}
+
+// IsConstraint returns true if the resolved version differs from the requested one,
+// meaning a major or minor locked constraint was resolved.
+func (resolution VersionResolutionModel) IsConstraint() bool {
+	return resolution.Constraint != "" && resolution.Constraint != resolution.Version
+}
+
+// ResolveVersion resolves the version constraint against the available versions,
+// the same way as a step reference of a bitrise.yml is resolved by stepman:
+// an empty constraint means the latest version, 8 or 8.x.x the latest 8 major version,
+// 8.1 or 8.1.x the latest 8.1 minor version. A full version has to be available as it is.
+func ResolveVersion(versions []string, latestVersion, constraint string) (VersionResolutionModel, error) {
+	resolution := VersionResolutionModel{Constraint: constraint}
+
+	if constraint == "" {
+		if latestVersion == "" {
+			return VersionResolutionModel{}, fmt.Errorf("no latest version found")
+		}
+		resolution.Version = latestVersion
+		resolution.Candidates = []string{latestVersion}
+		return resolution, nil
+	}
+
+	for _, version := range versions {
+		if version == constraint {
+			resolution.Version = version
+			resolution.Candidates = []string{version}
+			return resolution, nil
+		}
+	}
+
+	required, err := models.ParseRequiredVersion(constraint)
+	if err != nil {
+		return VersionResolutionModel{}, fmt.Errorf("invalid version constraint (%s): %s", constraint, err)
+	}
+
+	var candidates []semver
+	for _, version := range versions {
+		parsed, ok := parseSemver(version)
+		if !ok || !matchesConstraint(parsed, required) {
+			continue
+		}
+		candidates = append(candidates, parsed)
+	}
+	if len(candidates) == 0 {
+		return VersionResolutionModel{}, fmt.Errorf("no version found matching: %s", constraint)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[j].less(candidates[i])
+	})
+	for _, candidate := range candidates {
+		resolution.Candidates = append(resolution.Candidates, candidate.raw)
+	}
+	resolution.Version = resolution.Candidates[0]
+	return resolution, nil
+}
+
+// ResolveStepVersion resolves the version constraint of the step in the collection.
+func ResolveStepVersion(collection models.StepCollectionModel, stepID, constraint string) (VersionResolutionModel, error) {
+	stepGroup, found := collection.Steps[stepID]
+	if !found {
+		return VersionResolutionModel{}, fmt.Errorf("no step found for ID: %s", stepID)
+	}
+
+	versions := make([]string, 0, len(stepGroup.Versions))
+	for version := range stepGroup.Versions {
+		versions = append(versions, version)
+	}
+
+	resolution, err := ResolveVersion(versions, stepGroup.LatestVersionNumber, constraint)
+	if err != nil {
+		return VersionResolutionModel{}, fmt.Errorf("no step version found for (ID: %s) (version: %s): %s", stepID, constraint, err)
+	}
+	return resolution, nil
+}
+
+// StepVersion resolves the version constraint of the step in the collection, and returns
+// the matching step version. The step is normalized and its missing defaults are filled,
+// the same way as for a parsed step.yml.
+func StepVersion(collection models.StepCollectionModel, stepID, constraint string) (models.StepModel, VersionResolutionModel, error) {
+	resolution, err := ResolveStepVersion(collection, stepID, constraint)
+	if err != nil {
+		return models.StepModel{}, VersionResolutionModel{}, err
+	}
+
+	step, found := collection.Steps[stepID].Versions[resolution.Version]
+	if !found {
+		return models.StepModel{}, VersionResolutionModel{}, fmt.Errorf("no step version found for (ID: %s) (version: %s)", stepID, resolution.Version)
+	}
+	step, err = prepareStep(step, stepID, resolution.Version)
+	if err != nil {
+		return models.StepModel{}, VersionResolutionModel{}, err
+	}
+	return step, resolution, nil
+}
+
+// prepareStep normalizes the step and fills its missing defaults.
+func prepareStep(step models.StepModel, stepID, version string) (models.StepModel, error) {
+	if err := step.Normalize(); err != nil {
+		return models.StepModel{}, fmt.Errorf("failed to normalize step (%s@%s): %s", stepID, version, err)
+	}
+	if err := step.FillMissingDefaults(); err != nil {
+		return models.StepModel{}, fmt.Errorf("failed to fill missing defaults of step (%s@%s): %s", stepID, version, err)
+	}
+	return step, nil
+}
+
+// semver is a X.Y.Z version, with its original format
+type semver struct {
+	raw                 string
+	major, minor, patch uint64
+}
+
+func parseSemver(version string) (semver, bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+
+	var components [3]uint64
+	for i, part := range parts {
+		component, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return semver{}, false
+		}
+		components[i] = component
+	}
+	return semver{raw: version, major: components[0], minor: components[1], patch: components[2]}, true
+}
+
+func (v semver) less(other semver) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.patch < other.patch
+}
+
+func matchesConstraint(version semver, constraint models.VersionConstraint) bool {
+	switch constraint.VersionLockType {
+	case models.Latest:
+		return true
+	case models.MajorLocked:
+		return version.major == constraint.Version.Major
+	case models.MinorLocked:
+		return version.major == constraint.Version.Major && version.minor == constraint.Version.Minor
+	case models.Fixed:
+		return version.major == constraint.Version.Major &&
+			version.minor == constraint.Version.Minor &&
+			version.patch == constraint.Version.Patch
+	}
+	return false
+}
+
+// SortVersions sorts the versions semantically, highest first.
+// Versions which are not in X.Y.Z format come last, in alphabetical order.
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, iOK := parseSemver(versions[i])
+		vj, jOK := parseSemver(versions[j])
+		switch {
+		case iOK && jOK:
+			return vj.less(vi)
+		case iOK != jOK:
+			return iOK
+		}
+		return versions[i] < versions[j]
+	})
+}
+
+// CompareVersions compares two versions semantically. Missing minor and patch
+// components are treated as 0 (8.1 is the same as 8.1.0).
+// Returns -1 if v1 is lower than v2, 1 if it's higher and 0 if they are the same.
+func CompareVersions(v1, v2 string) (int, error) {
+	parsed1, ok := parsePartialSemver(v1)
+	if !ok {
+		return 0, fmt.Errorf("invalid version: %s", v1)
+	}
+	parsed2, ok := parsePartialSemver(v2)
+	if !ok {
+		return 0, fmt.Errorf("invalid version: %s", v2)
+	}
+
+	switch {
+	case parsed1.less(parsed2):
+		return -1, nil
+	case parsed2.less(parsed1):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func parsePartialSemver(version string) (semver, bool) {
+	parts := strings.Split(version, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	parsed, ok := parseSemver(strings.Join(parts, "."))
+	parsed.raw = version
+	return parsed, ok
+}
@@ -0,0 +1,265 @@
+package steprun
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/envman/cli"
+	"github.com/bitrise-io/envman/env"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/stepman/models"
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/pkg/errors"
+)
+
+const defaultBashEntryFile = "step.sh"
+
+// ConfigModel describes a local step run.
+type ConfigModel struct {
+	StepYMLPath string
+	// Inputs override the default values of the step inputs, by input key
+	Inputs map[string]string
+	// Envs are added to the current environment, the inputs can reference them
+	Envs   map[string]string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// environmentSource is the current environment, with additional envs.
+type environmentSource struct {
+	envs map[string]string
+}
+
+// GetEnvironment ...
+func (source environmentSource) GetEnvironment() map[string]string {
+	envs := (&env.DefaultEnvironmentSource{}).GetEnvironment()
+	for key, value := range source.envs {
+		envs[key] = value
+	}
+	return envs
+}
+
+// OutputModel is an env exported by the step.
+type OutputModel struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	IsSensitive bool   `json:"is_sensitive"`
+	// IsDeclared is false if the env is not an output of the step.yml
+	IsDeclared bool `json:"is_declared"`
+}
+
+// ResultModel ...
+type ResultModel struct {
+	ExitCode int           `json:"exit_code"`
+	Outputs  []OutputModel `json:"outputs"`
+}
+
+// ParseInputs parses key=value input arguments.
+func ParseInputs(args []string) (map[string]string, error) {
+	inputs := map[string]string{}
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || key == "" {
+			return nil, errors.Errorf("Invalid input (%s), should be in key=value format", arg)
+		}
+		inputs[key] = value
+	}
+	return inputs, nil
+}
+
+// InputEnvs returns the environment of the step: the environment of the envSource, with the step inputs.
+// The inputs are the default values of the step.yml, overridden by the given inputs, and are expanded
+// (unless their is_expand option is false) the same way as by the bitrise CLI.
+func InputEnvs(step models.StepModel, inputs map[string]string, envSource env.EnvironmentSource) (map[string]string, error) {
+	var inputEnvs []envmanModels.EnvironmentItemModel
+	overridden := map[string]bool{}
+	requiredKeys := []string{}
+	for _, input := range step.Inputs {
+		key, _, err := input.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read input")
+		}
+		options, err := input.GetOptions()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read options of input %s", key)
+		}
+		if pointers.BoolWithDefault(options.IsRequired, envmanModels.DefaultIsRequired) {
+			requiredKeys = append(requiredKeys, key)
+		}
+
+		if value, found := inputs[key]; found {
+			input = envmanModels.EnvironmentItemModel{key: value, envmanModels.OptionsKey: options}
+			overridden[key] = true
+		}
+		inputEnvs = append(inputEnvs, input)
+	}
+
+	var unknownKeys []string
+	for key := range inputs {
+		if !overridden[key] {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	if len(unknownKeys) > 0 {
+		sort.Strings(unknownKeys)
+		return nil, errors.Errorf("Unknown input(s): %s", strings.Join(unknownKeys, ", "))
+	}
+
+	result, err := env.GetDeclarationsSideEffects(inputEnvs, envSource)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to expand inputs")
+	}
+
+	for _, key := range requiredKeys {
+		if result.EvaluatedNewEnvs[key] == "" {
+			return nil, errors.Errorf("Required input (%s) is empty", key)
+		}
+	}
+	return result.ResultEnvironment, nil
+}
+
+// Run runs the step of the step.yml in the current directory, the way the bitrise CLI runs it.
+// Bash steps are run with bash, Go steps are built with go build and run from the build directory.
+// The outputs of the step are exported with envman, into an env store created for the run.
+// An error is only returned if the step could not be run, the exit code of the step is in the result.
+func Run(config ConfigModel) (ResultModel, error) {
+	step, err := stepman.ParseStepDefinition(config.StepYMLPath, false)
+	if err != nil {
+		return ResultModel{}, errors.Wrapf(err, "Failed to parse step.yml (%s)", config.StepYMLPath)
+	}
+	stepDir, err := filepath.Abs(filepath.Dir(config.StepYMLPath))
+	if err != nil {
+		return ResultModel{}, err
+	}
+
+	envs, err := InputEnvs(step, config.Inputs, environmentSource{envs: config.Envs})
+	if err != nil {
+		return ResultModel{}, err
+	}
+
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("step-run")
+	if err != nil {
+		return ResultModel{}, errors.Wrap(err, "Failed to create temporary directory")
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	envStorePth := filepath.Join(tmpDir, "envstore.yml")
+	if err := cli.InitEnvStore(envStorePth, false); err != nil {
+		return ResultModel{}, errors.Wrap(err, "Failed to create env store")
+	}
+	envs[cli.PathEnvKey] = envStorePth
+
+	entry, err := prepareEntry(step, stepDir, tmpDir, config.Stdout, config.Stderr)
+	if err != nil {
+		return ResultModel{}, err
+	}
+
+	cmd := command.New(entry[0], entry[1:]...).
+		SetEnvs(envList(envs)...).
+		SetStdin(config.Stdin).
+		SetStdout(config.Stdout).
+		SetStderr(config.Stderr)
+	exitCode, err := cmd.RunAndReturnExitCode()
+	if err != nil {
+		// the exit code of the step is part of the result, any other error means that the step could not be run
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitCode == -1 {
+			return ResultModel{}, errors.Wrapf(err, "Failed to run %s", cmd.PrintableCommandArgs())
+		}
+	}
+
+	outputs, err := ReadOutputs(step, envStorePth)
+	if err != nil {
+		return ResultModel{}, err
+	}
+	return ResultModel{ExitCode: exitCode, Outputs: outputs}, nil
+}
+
+// ReadOutputs returns the envs exported into the env store, in the order of export.
+// Envs are sensitive if they were exported as sensitive, or if the step.yml declares them as sensitive outputs.
+func ReadOutputs(step models.StepModel, envStorePth string) ([]OutputModel, error) {
+	declared := map[string]bool{}
+	for _, output := range step.Outputs {
+		key, _, err := output.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read output")
+		}
+		options, err := output.GetOptions()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read options of output %s", key)
+		}
+		declared[key] = pointers.BoolWithDefault(options.IsSensitive, envmanModels.DefaultIsSensitive)
+	}
+
+	envs, err := cli.ReadEnvs(envStorePth)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the exported envs")
+	}
+
+	var outputs []OutputModel
+	for _, exported := range envs {
+		key, value, err := exported.GetKeyValuePair()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read exported env")
+		}
+		options, err := exported.GetOptions()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read options of exported env %s", key)
+		}
+		isSensitiveOutput, isDeclared := declared[key]
+		outputs = append(outputs, OutputModel{
+			Key:         key,
+			Value:       value,
+			IsSensitive: isSensitiveOutput || pointers.BoolWithDefault(options.IsSensitive, envmanModels.DefaultIsSensitive),
+			IsDeclared:  isDeclared,
+		})
+	}
+	return outputs, nil
+}
+
+// prepareEntry returns the command line running the step: the bash toolkit's entry file,
+// or the binary of the Go toolkit's package, built into the buildDir.
+func prepareEntry(step models.StepModel, stepDir, buildDir string, stdout, stderr io.Writer) ([]string, error) {
+	toolkit := step.Toolkit
+	switch {
+	case toolkit == nil || toolkit.Bash != nil:
+		entryFile := defaultBashEntryFile
+		if toolkit != nil && toolkit.Bash.EntryFile != "" {
+			entryFile = toolkit.Bash.EntryFile
+		}
+		return []string{"bash", filepath.Join(stepDir, entryFile)}, nil
+	case toolkit.Go != nil:
+		if exist, err := pathutil.IsPathExists(filepath.Join(stepDir, "go.mod")); err != nil {
+			return nil, err
+		} else if !exist {
+			return nil, errors.Errorf("Go step (%s) has no go.mod, only Go module steps can be run", toolkit.Go.PackageName)
+		}
+
+		binPth := filepath.Join(buildDir, "step")
+		cmd := command.New("go", "build", "-o", binPth, ".").SetDir(stepDir).SetStdout(stdout).SetStderr(stderr)
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "Failed to build Go step (%s)", toolkit.Go.PackageName)
+		}
+		return []string{binPth}, nil
+	}
+	return nil, errors.New("Only bash and go toolkit steps can be run")
+}
+
+func envList(envs map[string]string) []string {
+	list := make([]string, 0, len(envs))
+	for key, value := range envs {
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
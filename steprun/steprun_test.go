@@ -0,0 +1,92 @@
+package steprun
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bitrise-io/stepman/stepman"
+	"github.com/stretchr/testify/require"
+)
+
+type envSource map[string]string
+
+func (source envSource) GetEnvironment() map[string]string {
+	envs := map[string]string{}
+	for key, value := range source {
+		envs[key] = value
+	}
+	return envs
+}
+
+func TestParseInputs(t *testing.T) {
+	inputs, err := ParseInputs([]string{"name=World", "greeting=a=b", "empty="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "World", "greeting": "a=b", "empty": ""}, inputs)
+
+	_, err = ParseInputs([]string{"name"})
+	require.Error(t, err)
+
+	_, err = ParseInputs([]string{"=World"})
+	require.Error(t, err)
+}
+
+func TestInputEnvs(t *testing.T) {
+	step, err := stepman.ParseStepDefinition("testdata/bash-step/step.yml", false)
+	require.NoError(t, err)
+
+	envs, err := InputEnvs(step, nil, envSource{"GREETER_NAME": "Bitrise", "PATH": "/bin"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"GREETER_NAME": "Bitrise",
+		"PATH":         "/bin",
+		"name":         "Bitrise",
+		"greeting":     "Hello $GREETER_NAME",
+		"exit_code":    "0",
+	}, envs)
+
+	envs, err = InputEnvs(step, map[string]string{"name": "$USER", "exit_code": "1"}, envSource{"USER": "me"})
+	require.NoError(t, err)
+	require.Equal(t, "me", envs["name"])
+	require.Equal(t, "1", envs["exit_code"])
+
+	_, err = InputEnvs(step, nil, envSource{})
+	require.EqualError(t, err, "Required input (name) is empty")
+
+	_, err = InputEnvs(step, map[string]string{"name": "me", "nmae": "me"}, envSource{})
+	require.EqualError(t, err, "Unknown input(s): nmae")
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	result, err := Run(ConfigModel{
+		StepYMLPath: "testdata/bash-step/step.yml",
+		Inputs:      map[string]string{"name": "World"},
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+	})
+	require.NoError(t, err, stderr.String())
+	require.Equal(t, 0, result.ExitCode)
+	require.Equal(t, "name: World\ngreeting: Hello $GREETER_NAME\n", stdout.String())
+	require.Equal(t, []OutputModel{
+		{Key: "GREETING", Value: "Hello World", IsDeclared: true},
+		{Key: "SECRET_TOKEN", Value: "token", IsSensitive: true},
+	}, result.Outputs)
+
+	result, err = Run(ConfigModel{
+		StepYMLPath: "testdata/bash-step/step.yml",
+		Inputs:      map[string]string{"name": "World", "exit_code": "3"},
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.ExitCode)
+
+	t.Setenv("PATH", "")
+	_, err = Run(ConfigModel{
+		StepYMLPath: "testdata/bash-step/step.yml",
+		Inputs:      map[string]string{"name": "World"},
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+	})
+	require.Error(t, err)
+}
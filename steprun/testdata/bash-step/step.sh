@@ -0,0 +1,16 @@
+#!/bin/bash
+set -e
+
+echo "name: ${name}"
+echo "greeting: ${greeting}"
+
+# exports the output the same way as envman add --key GREETING --value ...
+cat > "${ENVMAN_ENVSTORE_PATH}" <<ENVSTORE
+envs:
+- GREETING: Hello ${name}
+- SECRET_TOKEN: token
+  opts:
+    is_sensitive: true
+ENVSTORE
+
+exit "${exit_code}"
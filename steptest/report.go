@@ -0,0 +1,163 @@
+package steptest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReportModel is the JSON report of a test run.
+type ReportModel struct {
+	Name            string            `json:"name"`
+	Tests           int               `json:"tests"`
+	Failures        int               `json:"failures"`
+	Errors          int               `json:"errors"`
+	DurationSeconds float64           `json:"duration_seconds"`
+	Cases           []CaseReportModel `json:"cases"`
+}
+
+// CaseReportModel is the JSON report of a test case.
+type CaseReportModel struct {
+	Name            string   `json:"name"`
+	Passed          bool     `json:"passed"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	ExitCode        int      `json:"exit_code"`
+	Failures        []string `json:"failures,omitempty"`
+	Error           string   `json:"error,omitempty"`
+	Stdout          string   `json:"stdout"`
+	Stderr          string   `json:"stderr"`
+}
+
+// NewReport summarizes the results of the test cases, the name is the name of the test suite.
+func NewReport(name string, results []CaseResultModel) ReportModel {
+	report := ReportModel{Name: name, Cases: []CaseReportModel{}}
+	var duration time.Duration
+	for _, result := range results {
+		report.Tests++
+		if result.Error != "" {
+			report.Errors++
+		} else if len(result.Failures) > 0 {
+			report.Failures++
+		}
+		duration += result.Duration
+
+		report.Cases = append(report.Cases, CaseReportModel{
+			Name:            result.Name,
+			Passed:          result.Passed(),
+			DurationSeconds: result.Duration.Seconds(),
+			ExitCode:        result.ExitCode,
+			Failures:        result.Failures,
+			Error:           result.Error,
+			Stdout:          result.Stdout,
+			Stderr:          result.Stderr,
+		})
+	}
+	report.DurationSeconds = duration.Seconds()
+	return report
+}
+
+// WriteJSON writes the report as JSON.
+func (report ReportModel) WriteJSON(pth string) error {
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Failed to serialize JSON report")
+	}
+	if err := os.WriteFile(pth, append(bytes, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write JSON report (%s)", pth)
+	}
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// JUnitXML returns the report in JUnit XML format, with a single test suite.
+func (report ReportModel) JUnitXML() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:     report.Name,
+		Tests:    report.Tests,
+		Failures: report.Failures,
+		Errors:   report.Errors,
+		Time:     junitTime(report.DurationSeconds),
+	}
+	for _, testCase := range report.Cases {
+		junitCase := junitTestCase{
+			Name:      testCase.Name,
+			ClassName: report.Name,
+			Time:      junitTime(testCase.DurationSeconds),
+			SystemOut: testCase.Stdout,
+			SystemErr: testCase.Stderr,
+		}
+		if testCase.Error != "" {
+			junitCase.Error = &junitMessage{Message: testCase.Error, Content: testCase.Error}
+		} else if len(testCase.Failures) > 0 {
+			junitCase.Failure = &junitMessage{Message: testCase.Failures[0], Content: strings.Join(testCase.Failures, "\n")}
+		}
+		suite.TestCases = append(suite.TestCases, junitCase)
+	}
+
+	bytes, err := xml.MarshalIndent(junitTestSuites{
+		Name:     report.Name,
+		Tests:    report.Tests,
+		Failures: report.Failures,
+		Errors:   report.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to serialize JUnit report")
+	}
+	return append([]byte(xml.Header), append(bytes, '\n')...), nil
+}
+
+// WriteJUnit writes the report in JUnit XML format.
+func (report ReportModel) WriteJUnit(pth string) error {
+	bytes, err := report.JUnitXML()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(pth, bytes, 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write JUnit report (%s)", pth)
+	}
+	return nil
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
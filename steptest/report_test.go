@@ -0,0 +1,40 @@
+package steptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	report := NewReport("step_test.yml", []CaseResultModel{
+		{Name: "passes", Duration: 1500 * time.Millisecond, Stdout: "ok\n"},
+		{Name: "fails", Duration: 500 * time.Millisecond, ExitCode: 1, Failures: []string{"exit code: expected 0, got 1", "output A: not exported"}},
+		{Name: "errors", Error: "Unknown input(s): x"},
+	})
+	require.Equal(t, 3, report.Tests)
+	require.Equal(t, 1, report.Failures)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, 2.0, report.DurationSeconds)
+	require.True(t, report.Cases[0].Passed)
+	require.False(t, report.Cases[1].Passed)
+
+	junit, err := report.JUnitXML()
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="step_test.yml" tests="3" failures="1" errors="1" time="2.000">
+  <testsuite name="step_test.yml" tests="3" failures="1" errors="1" time="2.000">
+    <testcase name="passes" classname="step_test.yml" time="1.500">
+      <system-out>ok&#xA;</system-out>
+    </testcase>
+    <testcase name="fails" classname="step_test.yml" time="0.500">
+      <failure message="exit code: expected 0, got 1">exit code: expected 0, got 1&#xA;output A: not exported</failure>
+    </testcase>
+    <testcase name="errors" classname="step_test.yml" time="0.000">
+      <error message="Unknown input(s): x">Unknown input(s): x</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, string(junit))
+}
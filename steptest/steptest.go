@@ -0,0 +1,169 @@
+package steptest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-plugins-step/steprun"
+)
+
+// DefaultConfigPath is the test config of the step in the current directory
+const DefaultConfigPath = "step_test.yml"
+
+// CaseModel is a test case: a step run with the given inputs and envs, and its expected results.
+type CaseModel struct {
+	Name   string            `yaml:"name"`
+	Inputs map[string]string `yaml:"inputs,omitempty"`
+	Envs   map[string]string `yaml:"envs,omitempty"`
+	// ExitCode is the expected exit code, 0 if not specified
+	ExitCode int `yaml:"exit_code,omitempty"`
+	// Outputs are the expected values of the exported outputs, by key
+	Outputs map[string]string `yaml:"outputs,omitempty"`
+	// Stdout are regular expressions, each of them has to match the step's standard output
+	Stdout []string `yaml:"stdout,omitempty"`
+}
+
+// ConfigModel is the content of a step_test.yml.
+type ConfigModel struct {
+	// StepYML is the path of the tested step.yml, relative to the step_test.yml, step.yml if not specified
+	StepYML string      `yaml:"step_yml,omitempty"`
+	Cases   []CaseModel `yaml:"cases"`
+}
+
+// CaseResultModel is the result of a test case.
+type CaseResultModel struct {
+	Name     string
+	Duration time.Duration
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	// Failures are the unmet expectations of the case
+	Failures []string
+	// Error is set if the step could not be run
+	Error string
+}
+
+// Passed ...
+func (result CaseResultModel) Passed() bool {
+	return result.Error == "" && len(result.Failures) == 0
+}
+
+// ReadConfig reads and validates the step_test.yml.
+func ReadConfig(pth string) (ConfigModel, error) {
+	bytes, err := os.ReadFile(pth)
+	if err != nil {
+		return ConfigModel{}, errors.Wrapf(err, "Failed to read test config (%s)", pth)
+	}
+
+	var config ConfigModel
+	if err := yaml.UnmarshalStrict(bytes, &config); err != nil {
+		return ConfigModel{}, errors.Wrapf(err, "Failed to parse test config (%s)", pth)
+	}
+	if config.StepYML == "" {
+		config.StepYML = "step.yml"
+	}
+	if len(config.Cases) == 0 {
+		return ConfigModel{}, errors.Errorf("No test case defined in %s", pth)
+	}
+
+	names := map[string]bool{}
+	for i, testCase := range config.Cases {
+		if testCase.Name == "" {
+			return ConfigModel{}, errors.Errorf("Test case #%d has no name", i+1)
+		}
+		if names[testCase.Name] {
+			return ConfigModel{}, errors.Errorf("Test case name (%s) is not unique", testCase.Name)
+		}
+		names[testCase.Name] = true
+
+		for _, pattern := range testCase.Stdout {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return ConfigModel{}, errors.Wrapf(err, "Invalid stdout pattern of test case (%s)", testCase.Name)
+			}
+		}
+	}
+	return config, nil
+}
+
+// StepYMLPath returns the path of the tested step.yml, the step_test.yml is at configPth.
+func (config ConfigModel) StepYMLPath(configPth string) string {
+	if filepath.IsAbs(config.StepYML) {
+		return config.StepYML
+	}
+	return filepath.Join(filepath.Dir(configPth), config.StepYML)
+}
+
+// RunCase runs the step of the step.yml with the inputs and envs of the test case, and checks the expectations.
+// The step's output is written to the log as well, if it's not nil.
+func RunCase(stepYMLPth string, testCase CaseModel, log io.Writer) CaseResultModel {
+	var stdout, stderr bytes.Buffer
+	stdoutWriter, stderrWriter := io.Writer(&stdout), io.Writer(&stderr)
+	if log != nil {
+		stdoutWriter, stderrWriter = io.MultiWriter(&stdout, log), io.MultiWriter(&stderr, log)
+	}
+
+	startTime := time.Now()
+	runResult, err := steprun.Run(steprun.ConfigModel{
+		StepYMLPath: stepYMLPth,
+		Inputs:      testCase.Inputs,
+		Envs:        testCase.Envs,
+		Stdout:      stdoutWriter,
+		Stderr:      stderrWriter,
+	})
+	result := CaseResultModel{
+		Name:     testCase.Name,
+		Duration: time.Since(startTime),
+		ExitCode: runResult.ExitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Failures = checkExpectations(testCase, runResult, result.Stdout)
+	return result
+}
+
+func checkExpectations(testCase CaseModel, runResult steprun.ResultModel, stdout string) []string {
+	var failures []string
+	if runResult.ExitCode != testCase.ExitCode {
+		failures = append(failures, fmt.Sprintf("exit code: expected %d, got %d", testCase.ExitCode, runResult.ExitCode))
+	}
+
+	exported := map[string]steprun.OutputModel{}
+	for _, output := range runResult.Outputs {
+		exported[output.Key] = output
+	}
+	for _, key := range slices.Sorted(maps.Keys(testCase.Outputs)) {
+		output, found := exported[key]
+		switch {
+		case !found:
+			failures = append(failures, fmt.Sprintf("output %s: not exported", key))
+		case output.Value == testCase.Outputs[key]:
+		case output.IsSensitive:
+			// sensitive values are not revealed, neither in the log nor in the reports
+			failures = append(failures, fmt.Sprintf("output %s: sensitive value does not match the expected value", key))
+		default:
+			failures = append(failures, fmt.Sprintf("output %s: expected %q, got %q", key, testCase.Outputs[key], output.Value))
+		}
+	}
+
+	for _, pattern := range testCase.Stdout {
+		// patterns are validated when reading the config
+		if !regexp.MustCompile(pattern).MatchString(stdout) {
+			failures = append(failures, fmt.Sprintf("stdout: no match for %q", pattern))
+		}
+	}
+	return failures
+}
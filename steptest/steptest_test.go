@@ -0,0 +1,61 @@
+package steptest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfig(t *testing.T) {
+	config, err := ReadConfig("testdata/greeter/step_test.yml")
+	require.NoError(t, err)
+	require.Equal(t, "step.yml", config.StepYML)
+	require.Equal(t, filepath.Join("testdata", "greeter", "step.yml"), config.StepYMLPath("testdata/greeter/step_test.yml"))
+	require.Equal(t, 5, len(config.Cases))
+	require.Equal(t, CaseModel{
+		Name:     "fails",
+		Inputs:   map[string]string{"name": "World", "exit_code": "1"},
+		ExitCode: 1,
+	}, config.Cases[2])
+
+	for content, wantErr := range map[string]string{
+		"cases: []\n":                               "No test case defined",
+		"cases:\n- inputs: {}\n":                    "Test case #1 has no name",
+		"cases:\n- name: a\n- name: a\n":            "Test case name (a) is not unique",
+		"cases:\n- name: a\n  stdout:\n  - \"(\"\n": "Invalid stdout pattern of test case (a)",
+		"cases:\n- name: a\n  exit-code: 1\n":       "Failed to parse test config",
+	} {
+		pth := filepath.Join(t.TempDir(), "step_test.yml")
+		require.NoError(t, os.WriteFile(pth, []byte(content), 0644))
+		_, err := ReadConfig(pth)
+		require.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestRunCase(t *testing.T) {
+	config, err := ReadConfig("testdata/greeter/step_test.yml")
+	require.NoError(t, err)
+	stepYMLPth := config.StepYMLPath("testdata/greeter/step_test.yml")
+
+	for _, testCase := range config.Cases[:3] {
+		result := RunCase(stepYMLPth, testCase, nil)
+		require.True(t, result.Passed(), "%s: %v %s", testCase.Name, result.Failures, result.Error)
+	}
+
+	result := RunCase(stepYMLPth, config.Cases[3], nil)
+	require.False(t, result.Passed())
+	require.Equal(t, []string{
+		"exit code: expected 2, got 0",
+		`output GREETING: expected "Hi World", got "Hello World"`,
+		"output MISSING: not exported",
+		"output SECRET_TOKEN: sensitive value does not match the expected value",
+		`stdout: no match for "^Hi"`,
+	}, result.Failures)
+	require.Equal(t, "name: World\ngreeting: Hello $GREETER_NAME\n", result.Stdout)
+
+	result = RunCase(stepYMLPth, config.Cases[4], nil)
+	require.False(t, result.Passed())
+	require.Equal(t, "Unknown input(s): nmae", result.Error)
+}